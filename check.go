@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/markelog/map/check"
+	"github.com/markelog/map/io"
+	"github.com/markelog/map/print"
+	"github.com/markelog/map/spider"
+)
+
+// Number of the broken links which is still fine
+var maxBroken int
+
+// Patterns of the broken links to ignore
+var ignore []string
+
+// Broken external links fail the check as well
+var external bool
+
+// Path of the JUnit report
+var junit string
+
+// Check command example
+const checkExample = `
+  Fail if there is any broken link on the site
+  $ map check https://example.com
+
+  Allow some of them and ignore the others
+  $ map check https://example.com --max-broken=5 --ignore='^https?://localhost' --ignore='\.pdf$'
+
+  Check external links as well and let the CI show them as failed tests
+  $ map check https://example.com --external --junit=./map.xml
+
+  Broken images, scripts and styles fail the check too
+  $ map check https://example.com --check-assets
+`
+
+// CheckCommand config
+var CheckCommand = &cobra.Command{
+	Use:     "check https://example.com",
+	Short:   "Check the links, exits with 1 if there are broken internal links",
+	Example: checkExample,
+	Run:     Check,
+}
+
+// Check the links
+func Check(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		print.Error(errors.New("Target is not specified"), 2)
+
+		return
+	}
+
+	patterns := []*regexp.Regexp{}
+	for _, pattern := range ignore {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			print.Error(errors.New(`Incorrect "`+pattern+`" ignore pattern: `+err.Error()), 2)
+		}
+
+		patterns = append(patterns, compiled)
+	}
+
+	crawler := newCrawler(args[0], spider.WithCheckExternal(external))
+
+	// Stop the crawl on interrupt, but still check what we got so far
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go interrupt(cancel)
+
+	exitCode := print.Spin(crawler.CrawlContext(ctx))
+	if ctx.Err() != nil && exitCode == 0 {
+		exitCode = 130
+	}
+
+	var report *check.Report
+
+	// Root is the failed test case if the site can't be crawled
+	data, err := crawler.Get()
+	if data == nil {
+		if err == nil {
+			err = errors.New("Can't crawl " + args[0])
+		}
+
+		report = check.Unreachable(args[0], err)
+	} else {
+		report = check.Check(data, check.Options{
+			Domains:   strings.Split(domains, ","),
+			Ignore:    patterns,
+			External:  external,
+			MaxBroken: maxBroken,
+		})
+	}
+
+	fmt.Println(check.Text(report))
+
+	if len(junit) > 0 {
+		serialized, err := check.JUnit(report)
+		print.Error(err, 1)
+		print.Error(io.WriteFile(junit, serialized), 1)
+	}
+
+	if report.Failed() {
+		exitCode = 1
+	}
+
+	os.Exit(exitCode)
+}
+
+func init() {
+	flags := CheckCommand.Flags()
+
+	crawlFlags(flags)
+
+	flags.IntVar(
+		&maxBroken,
+		"max-broken",
+		0,
+		"How many broken links are still fine",
+	)
+
+	flags.StringArrayVar(
+		&ignore,
+		"ignore",
+		[]string{},
+		"Ignore broken links which match the regular expression, could be repeated",
+	)
+
+	flags.BoolVar(
+		&external,
+		"external",
+		false,
+		"Request the links to the other domains and fail the check if they are broken",
+	)
+
+	flags.StringVar(
+		&junit,
+		"junit",
+		"",
+		"Write JUnit XML report to the file, every broken link is a failed test case",
+	)
+
+	Command.AddCommand(CheckCommand)
+}
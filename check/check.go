@@ -0,0 +1,200 @@
+// Package check validates the links of the map
+package check
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/markelog/map/spider"
+)
+
+// Options of the check
+type Options struct {
+	// Domains which links are internal, besides the domain of the root
+	Domains []string
+
+	// Ignore the broken links which match any of the patterns
+	Ignore []*regexp.Regexp
+
+	// External broken links fail the check as well
+	External bool
+
+	// MaxBroken is the number of the broken links which is still fine
+	MaxBroken int
+}
+
+// Link is the broken link found on the page
+type Link struct {
+	*spider.Broken
+
+	// Page where the link was found
+	Page string
+
+	// Kind of the asset, like "images", empty for the links
+	Kind string
+
+	// Internal link leads to one of the domains
+	Internal bool
+
+	// Ignored link matches one of the patterns
+	Ignored bool
+
+	// Failed link fails the check, it is not ignored and it is
+	// either internal or external links are checked as well
+	Failed bool
+}
+
+// Report of the check
+type Report struct {
+	// Pages which were checked
+	Pages []string
+
+	// Links and assets which are broken
+	Links []*Link
+
+	// Failures is the number of the broken links which fail the check
+	Failures int
+
+	// MaxBroken is the threshold of the check
+	MaxBroken int
+}
+
+// Check the broken links of the map
+func Check(data *spider.Result, options Options) *Report {
+	var (
+		report  = &Report{MaxBroken: options.MaxBroken}
+		domains = map[string]bool{}
+	)
+
+	if root, err := url.Parse(data.URL); err == nil {
+		domains[root.Host] = true
+	}
+
+	for _, domain := range options.Domains {
+		if len(domain) > 0 {
+			domains[domain] = true
+		}
+	}
+
+	for _, node := range spider.Flatten(data) {
+		report.Pages = append(report.Pages, node.URL)
+
+		add := func(broken *spider.Broken, kind string) {
+			link := &Link{
+				Broken:   broken,
+				Page:     node.URL,
+				Kind:     kind,
+				Internal: isInternal(broken.URL, domains),
+				Ignored:  isIgnored(broken.URL, options.Ignore),
+			}
+
+			link.Failed = link.Ignored == false && (link.Internal || options.External)
+			if link.Failed {
+				report.Failures++
+			}
+
+			report.Links = append(report.Links, link)
+		}
+
+		for _, broken := range node.Broken {
+			add(broken, "")
+		}
+
+		for _, asset := range node.BrokenAssets {
+			add(&spider.Broken{
+				URL:        asset.URL,
+				StatusCode: asset.StatusCode,
+				Class:      asset.Class,
+				Message:    asset.Message,
+			}, asset.Kind)
+		}
+	}
+
+	return report
+}
+
+// Unreachable makes the report of the site which root couldn't be crawled,
+// the root is the only link and it fails the check no matter the threshold
+func Unreachable(root string, err error) *Report {
+	broken, ok := err.(*spider.Broken)
+	if ok == false {
+		broken = &spider.Broken{
+			URL:     root,
+			Class:   spider.ClassUnknown,
+			Message: err.Error(),
+		}
+	}
+
+	return &Report{
+		Pages: []string{root},
+		Links: []*Link{
+			{
+				Broken:   broken,
+				Page:     root,
+				Internal: true,
+				Failed:   true,
+			},
+		},
+		Failures: 1,
+	}
+}
+
+// Failed finds out if there is more failures than the threshold
+func (report *Report) Failed() bool {
+	return report.Failures > report.MaxBroken
+}
+
+// isInternal finds out if the link leads to one of the domains
+func isInternal(link string, domains map[string]bool) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	return domains[parsed.Host]
+}
+
+// isIgnored finds out if the link matches any of the patterns
+func isIgnored(link string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(link) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Text representation of the report
+func Text(report *Report) string {
+	var (
+		lines    = []string{}
+		failures = 0
+	)
+
+	for _, link := range report.Links {
+		line := link.URL + " (" + strings.TrimSpace(reason(link)) + ") on " + link.Page
+		if len(link.Kind) > 0 {
+			line = link.Kind + ": " + line
+		}
+
+		if link.Failed {
+			failures++
+			lines = append(lines, "✗ "+line)
+		} else {
+			lines = append(lines, "- "+line+", "+skipReason(link))
+		}
+	}
+
+	lines = append(lines, fmt.Sprintf(
+		"Checked %d pages: %d broken links, %d failing (%d allowed)",
+		len(report.Pages),
+		len(report.Links),
+		failures,
+		report.MaxBroken,
+	))
+
+	return strings.Join(lines, "\n")
+}
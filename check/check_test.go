@@ -0,0 +1,160 @@
+package check_test
+
+import (
+	"errors"
+	"regexp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/check"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("check", func() {
+	var data *spider.Result
+
+	BeforeEach(func() {
+		data = &spider.Result{
+			URL: "http://example.com",
+			Broken: []*spider.Broken{
+				{URL: "http://example.com/nope", StatusCode: 404, Message: "Not Found", Text: "Nope"},
+				{URL: "http://nope.example", Class: spider.ClassDNS, Message: "no such host"},
+			},
+			Children: []*spider.Result{
+				{
+					URL: "http://example.com/a",
+					Broken: []*spider.Broken{
+						{URL: "http://example.net/file.pdf", StatusCode: 500, Message: "Internal Server Error"},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("Check", func() {
+		It("Fails on broken internal links", func() {
+			report := Check(data, Options{})
+
+			Expect(report.Pages).To(Equal([]string{"http://example.com", "http://example.com/a"}))
+			Expect(report.Links).To(HaveLen(3))
+			Expect(report.Failures).To(Equal(1))
+			Expect(report.Failed()).To(Equal(true))
+
+			Expect(report.Links[0].Page).To(Equal("http://example.com"))
+			Expect(report.Links[0].Internal).To(Equal(true))
+			Expect(report.Links[0].Failed).To(Equal(true))
+			Expect(report.Links[1].Failed).To(Equal(false))
+		})
+
+		It("Considers additional domains as internal", func() {
+			report := Check(data, Options{Domains: []string{"example.net", ""}})
+
+			Expect(report.Failures).To(Equal(2))
+			Expect(report.Links[2].Page).To(Equal("http://example.com/a"))
+			Expect(report.Links[2].Internal).To(Equal(true))
+		})
+
+		It("Fails on broken external links if asked", func() {
+			report := Check(data, Options{External: true})
+
+			Expect(report.Failures).To(Equal(3))
+		})
+
+		It("Ignores the links", func() {
+			report := Check(data, Options{
+				External: true,
+				Ignore: []*regexp.Regexp{
+					regexp.MustCompile(`/nope$`),
+					regexp.MustCompile(`\.pdf$`),
+				},
+			})
+
+			Expect(report.Failures).To(Equal(1))
+			Expect(report.Links[0].Ignored).To(Equal(true))
+		})
+
+		It("Allows some of the links", func() {
+			report := Check(data, Options{External: true, MaxBroken: 3})
+
+			Expect(report.Failures).To(Equal(3))
+			Expect(report.Failed()).To(Equal(false))
+		})
+
+		It("Checks the broken assets", func() {
+			data.BrokenAssets = []*spider.BrokenAsset{
+				{URL: "http://example.com/a.png", Kind: "images", StatusCode: 404, Class: spider.ClassHTTP, Message: "Not Found"},
+			}
+
+			report := Check(data, Options{})
+
+			Expect(report.Failures).To(Equal(2))
+			Expect(report.Links[2].Kind).To(Equal("images"))
+			Expect(report.Links[2].Failed).To(Equal(true))
+			Expect(Text(report)).To(ContainSubstring(
+				"✗ images: http://example.com/a.png (404 Not Found) on http://example.com\n",
+			))
+		})
+	})
+
+	Describe("Unreachable", func() {
+		It("Fails on the root", func() {
+			report := Unreachable("http://example.com", &spider.Broken{
+				URL:        "http://example.com",
+				StatusCode: 503,
+				Class:      spider.ClassHTTP,
+				Message:    "Service Unavailable",
+			})
+
+			Expect(report.Failed()).To(Equal(true))
+
+			result, err := JUnit(report)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(ContainSubstring(`<testsuites name="map" tests="1" failures="1">`))
+			Expect(result).To(ContainSubstring(`<failure message="503 Service Unavailable" type="http">`))
+		})
+
+		It("Fails on the root with any error", func() {
+			report := Unreachable("http://example.com", errors.New("Disallowed"))
+
+			Expect(report.Failures).To(Equal(1))
+			Expect(Text(report)).To(HavePrefix("✗ http://example.com (Disallowed) on http://example.com"))
+		})
+	})
+
+	Describe("Text", func() {
+		It("Describes the report", func() {
+			Expect(Text(Check(data, Options{MaxBroken: 1}))).To(Equal(
+				`✗ http://example.com/nope (404 Not Found) on http://example.com
+- http://nope.example (no such host) on http://example.com, external
+- http://example.net/file.pdf (500 Internal Server Error) on http://example.com/a, external
+Checked 2 pages: 3 broken links, 1 failing (1 allowed)`,
+			))
+		})
+	})
+
+	Describe("JUnit", func() {
+		It("Makes the JUnit report", func() {
+			result, err := JUnit(Check(data, Options{}))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="map" tests="3" failures="1">
+  <testsuite name="http://example.com" tests="2" failures="1" skipped="1">
+    <testcase name="http://example.com/nope" classname="http://example.com">
+      <failure message="404 Not Found" type="">Found on http://example.com&#xA;Text: Nope</failure>
+    </testcase>
+    <testcase name="http://nope.example" classname="http://example.com">
+      <skipped message="external"></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="http://example.com/a" tests="1" failures="0" skipped="1">
+    <testcase name="http://example.net/file.pdf" classname="http://example.com/a">
+      <skipped message="external"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`))
+		})
+	})
+})
@@ -0,0 +1,140 @@
+package check
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// testSuites is the root of the JUnit report
+type testSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []*testSuite `xml:"testsuite"`
+}
+
+// testSuite has the test cases of one page
+type testSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []*testCase `xml:"testcase"`
+}
+
+// testCase is the broken link
+type testCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	Failure   *failure `xml:"failure,omitempty"`
+	Skipped   *skipped `xml:"skipped,omitempty"`
+}
+
+// failure of the test case
+type failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// skipped test case
+type skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// JUnit makes the JUnit XML report, every page is a test suite
+// and every broken link of it is a failed test case, the links
+// which don't fail the check are skipped
+func JUnit(report *Report) (string, error) {
+	var (
+		root  = &testSuites{Name: "map"}
+		pages = map[string]*testSuite{}
+	)
+
+	for _, page := range report.Pages {
+		if _, ok := pages[page]; ok {
+			continue
+		}
+
+		pages[page] = &testSuite{Name: page}
+		root.Suites = append(root.Suites, pages[page])
+	}
+
+	for _, link := range report.Links {
+		var (
+			suite = pages[link.Page]
+			test  = &testCase{
+				Name:      link.URL,
+				ClassName: link.Page,
+			}
+		)
+
+		if link.Failed {
+			test.Failure = &failure{
+				Message: reason(link),
+				Type:    link.Class,
+				Text:    details(link),
+			}
+			suite.Failures++
+		} else {
+			test.Skipped = &skipped{Message: skipReason(link)}
+			suite.Skipped++
+		}
+
+		suite.Tests++
+		suite.Cases = append(suite.Cases, test)
+	}
+
+	for _, suite := range root.Suites {
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+	}
+
+	result, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	return xml.Header + string(result), nil
+}
+
+// reason why the link is broken
+func reason(link *Link) string {
+	if link.StatusCode != 0 {
+		return strconv.Itoa(link.StatusCode) + " " + link.Message
+	}
+
+	return link.Message
+}
+
+// details of the broken link
+func details(link *Link) string {
+	result := []string{"Found on " + link.Page}
+
+	if len(link.Kind) > 0 {
+		result = append(result, "Asset: "+link.Kind)
+	}
+
+	if len(link.Selector) > 0 {
+		result = append(result, "Selector: "+link.Selector)
+	}
+
+	if len(link.Text) > 0 {
+		result = append(result, "Text: "+link.Text)
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// skipReason tells why the link doesn't fail the check
+func skipReason(link *Link) string {
+	if link.Ignored {
+		return "ignored"
+	}
+
+	return "external"
+}
@@ -3,15 +3,108 @@
 package collect
 
 import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly"
 )
 
+// Types of the assets
+const (
+	// AssetInternal is on the same host as the page
+	AssetInternal = "internal"
+
+	// AssetExternal is on the other host
+	AssetExternal = "external"
+
+	// AssetInline is embedded in the page, like <script> without "src"
+	AssetInline = "inline"
+
+	// AssetData is the data url
+	AssetData = "data"
+)
+
 // Collect settings
 type Collect struct {
-	doc *goquery.Document
+	doc  *goquery.Document
+	page *url.URL
+	base *url.URL
+}
+
+// Asset is the resolved url of the asset with its type,
+// inline assets have only the selector of the element
+type Asset struct {
+	URL      string `json:"url"`
+	Type     string `json:"type"`
+	Selector string `json:"selector,omitempty"`
+
+	// Descriptor and sizes of the image candidate, like "2x" and "100vw"
+	Descriptor string `json:"descriptor,omitempty"`
+	Sizes      string `json:"sizes,omitempty"`
+}
+
+// Anchor is the <a> element
+type Anchor struct {
+	URL      string
+	Text     string
+	Selector string
+	Rel      string
+}
+
+// Relations are the <link> elements, besides the stylesheets, by their "rel"
+type Relations struct {
+	Canonical  string
+	Alternates []*Alternate
+	Icons      []*Icon
+	Manifest   string
+	Preloads   []*Preload
+	Feeds      []*Feed
+}
+
+// Alternate version of the page, like the translation
+type Alternate struct {
+	URL      string `json:"url"`
+	Language string `json:"hreflang,omitempty"`
+	Media    string `json:"media,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+// Icon of the page
+type Icon struct {
+	URL   string `json:"url"`
+	Rel   string `json:"rel"`
+	Sizes string `json:"sizes,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+// Preload is the resource which browser is hinted to fetch in advance
+type Preload struct {
+	URL string `json:"url"`
+	Rel string `json:"rel"`
+	As  string `json:"as,omitempty"`
+}
+
+// Feed of the site
+type Feed struct {
+	URL   string `json:"url"`
+	Type  string `json:"type"`
+	Title string `json:"title,omitempty"`
 }
 
+// feeds are the content types of the feeds, plain "application/json"
+// is not there, it's used by the APIs, like the one of WordPress
+var feeds = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+}
+
+// preloads are the resource hints
+var preloads = []string{"preload", "prefetch", "modulepreload", "prerender", "preconnect", "dns-prefetch"}
+
 // New returns new Collect instance
 func New(doc *goquery.Document) *Collect {
 	return &Collect{
@@ -19,6 +112,47 @@ func New(doc *goquery.Document) *Collect {
 	}
 }
 
+// NewWithURL returns new Collect instance which resolves
+// the assets against the page url and its <base href>
+func NewWithURL(doc *goquery.Document, page string) *Collect {
+	collect := New(doc)
+
+	parsed, err := url.Parse(page)
+	if err != nil {
+		return collect
+	}
+
+	collect.page = parsed
+	collect.base = parsed
+
+	href, exist := doc.Find("base[href]").First().Attr("href")
+	if exist == false {
+		return collect
+	}
+
+	base, err := parsed.Parse(strings.TrimSpace(href))
+	if err == nil {
+		collect.base = base
+	}
+
+	return collect
+}
+
+// UnmarshalJSON supports the maps which were saved when assets were just the strings
+func (asset *Asset) UnmarshalJSON(data []byte) error {
+	var link string
+	if json.Unmarshal(data, &link) == nil {
+		asset.URL = link
+
+		return nil
+	}
+
+	// Prevent the recursion
+	type plain Asset
+
+	return json.Unmarshal(data, (*plain)(asset))
+}
+
 // Title gives text of the first <title>
 func (collect Collect) Title() (text string) {
 	title := collect.doc.Find("title")
@@ -30,26 +164,120 @@ func (collect Collect) Title() (text string) {
 	return title.Eq(0).Text()
 }
 
-// Images returns all <img> "src" attribute
-// TODO: Parse srcset attribute as well
+// Candidate is the image source with its descriptor, like "2x" or "480w"
+type Candidate struct {
+	URL        string
+	Descriptor string
+
+	// Sizes is the "sizes" attribute of the element
+	Sizes string
+}
+
+// lazy are the attributes of the lazy loaded images
+var lazy = []string{"data-src", "data-lazy-src", "data-original"}
+
+// lazySets are the "srcset" attributes of the lazy loaded images
+var lazySets = []string{"data-srcset", "data-lazy-srcset"}
+
+// Images returns all image urls of the <img> and <picture> elements
 func (collect Collect) Images() (images []string) {
-	collect.doc.Find("img").Each(func(i int, node *goquery.Selection) {
-		src, exist := node.Attr("src")
-		if exist == false {
-			return
+	for _, candidate := range collect.Candidates() {
+		images = append(images, candidate.URL)
+	}
+
+	return
+}
+
+// Candidates returns all image candidates of the <img> and <picture>
+// elements: "src", "srcset" and their lazy loaded counterparts
+func (collect Collect) Candidates() (candidates []*Candidate) {
+	collect.doc.Find("img, picture > source").Each(func(i int, node *goquery.Selection) {
+		var (
+			sizes, _ = node.Attr("sizes")
+			known    = map[string]bool{}
+		)
+
+		add := func(candidate *Candidate) {
+			if len(candidate.URL) == 0 || known[candidate.URL] {
+				return
+			}
+
+			known[candidate.URL] = true
+			candidate.Sizes = sizes
+			candidates = append(candidates, candidate)
 		}
 
-		images = append(images, src)
+		// <source> of the <picture> has only "srcset"
+		if goquery.NodeName(node) == "img" {
+			src, _ := node.Attr("src")
+			add(&Candidate{URL: strings.TrimSpace(src)})
+
+			for _, name := range lazy {
+				src, _ := node.Attr(name)
+				add(&Candidate{URL: strings.TrimSpace(src)})
+			}
+		}
+
+		for _, name := range append([]string{"srcset"}, lazySets...) {
+			srcset, _ := node.Attr(name)
+
+			for _, candidate := range ParseSrcset(srcset) {
+				add(candidate)
+			}
+		}
 	})
 
 	return
 }
 
-// Styles returns all <link> "href" attribute
+// ParseSrcset parses the "srcset" attribute into the candidates,
+// url might have commas in it, like the data url, but not the whitespace
+func ParseSrcset(srcset string) (candidates []*Candidate) {
+	isSpace := func(char byte) bool {
+		return char == ' ' || char == '\t' || char == '\n' || char == '\r' || char == '\f'
+	}
+
+	for position := 0; position < len(srcset); {
+		// Skip the whitespace and the commas before the url
+		for position < len(srcset) && (isSpace(srcset[position]) || srcset[position] == ',') {
+			position++
+		}
+
+		start := position
+		for position < len(srcset) && isSpace(srcset[position]) == false {
+			position++
+		}
+
+		url := srcset[start:position]
+		if len(url) == 0 {
+			break
+		}
+
+		// Url which ends with the comma has no descriptor
+		if strings.HasSuffix(url, ",") {
+			candidates = append(candidates, &Candidate{URL: strings.TrimRight(url, ",")})
+			continue
+		}
+
+		start = position
+		for position < len(srcset) && srcset[position] != ',' {
+			position++
+		}
+
+		candidates = append(candidates, &Candidate{
+			URL:        url,
+			Descriptor: strings.Join(strings.Fields(srcset[start:position]), " "),
+		})
+	}
+
+	return
+}
+
+// Styles returns "href" attribute of all <link rel="stylesheet">
 func (collect Collect) Styles() (links []string) {
-	collect.doc.Find("link").Each(func(i int, node *goquery.Selection) {
+	collect.doc.Find("link[rel]").Each(func(i int, node *goquery.Selection) {
 		href, exist := node.Attr("href")
-		if exist == false {
+		if exist == false || hasRel(node, "stylesheet") == false {
 			return
 		}
 
@@ -59,6 +287,78 @@ func (collect Collect) Styles() (links []string) {
 	return
 }
 
+// Relations returns the <link> elements by their "rel", urls are resolved
+func (collect Collect) Relations() *Relations {
+	relations := &Relations{}
+
+	collect.doc.Find("link[rel]").Each(func(i int, node *goquery.Selection) {
+		href, exist := node.Attr("href")
+		if exist == false {
+			return
+		}
+
+		var (
+			link        = collect.Resolve(href).URL
+			rel, _      = node.Attr("rel")
+			kind, _     = node.Attr("type")
+			language, _ = node.Attr("hreflang")
+			media, _    = node.Attr("media")
+			sizes, _    = node.Attr("sizes")
+			as, _       = node.Attr("as")
+			title, _    = node.Attr("title")
+		)
+
+		rel = strings.ToLower(strings.Join(strings.Fields(rel), " "))
+		kind = strings.ToLower(strings.TrimSpace(kind))
+
+		switch {
+		case hasRel(node, "stylesheet"):
+			return
+		case hasRel(node, "canonical"):
+			if len(relations.Canonical) == 0 {
+				relations.Canonical = link
+			}
+		case hasRel(node, "alternate") && feeds[kind]:
+			relations.Feeds = append(relations.Feeds, &Feed{
+				URL:   link,
+				Type:  kind,
+				Title: strings.TrimSpace(title),
+			})
+		case hasRel(node, "alternate"):
+			relations.Alternates = append(relations.Alternates, &Alternate{
+				URL:      link,
+				Language: language,
+				Media:    media,
+				Type:     kind,
+			})
+		case hasRel(node, "manifest"):
+			if len(relations.Manifest) == 0 {
+				relations.Manifest = link
+			}
+		case strings.Contains(rel, "icon"):
+			relations.Icons = append(relations.Icons, &Icon{
+				URL:   link,
+				Rel:   rel,
+				Sizes: sizes,
+				Type:  kind,
+			})
+		default:
+			for _, hint := range preloads {
+				if hasRel(node, hint) {
+					relations.Preloads = append(relations.Preloads, &Preload{
+						URL: link,
+						Rel: hint,
+						As:  as,
+					})
+					break
+				}
+			}
+		}
+	})
+
+	return relations
+}
+
 // Scripts returns all <script> "src" attribute
 func (collect Collect) Scripts() (scripts []string) {
 	collect.doc.Find("script").Each(func(i int, node *goquery.Selection) {
@@ -75,6 +375,15 @@ func (collect Collect) Scripts() (scripts []string) {
 
 // Links returns all <a> "href" attribute
 func (collect Collect) Links(request *colly.Request) (links []string) {
+	for _, anchor := range collect.Anchors(request) {
+		links = append(links, anchor.URL)
+	}
+
+	return
+}
+
+// Anchors returns all <a> elements with "href" attribute
+func (collect Collect) Anchors(request *colly.Request) (anchors []*Anchor) {
 	// a[href] selector should be slower
 	collect.doc.Find("a").Each(func(i int, node *goquery.Selection) {
 		href, exist := node.Attr("href")
@@ -82,7 +391,14 @@ func (collect Collect) Links(request *colly.Request) (links []string) {
 			return
 		}
 
-		links = append(links, request.AbsoluteURL(href))
+		rel, _ := node.Attr("rel")
+
+		anchors = append(anchors, &Anchor{
+			URL:      request.AbsoluteURL(href),
+			Text:     strings.Join(strings.Fields(node.Text()), " "),
+			Selector: selector(node),
+			Rel:      rel,
+		})
 	})
 
 	return
@@ -144,13 +460,139 @@ func (collect Collect) Audio() (audio []string) {
 	return
 }
 
-// Assets returns all available and supported by us assets
-func (collect Collect) Assets() map[string][]string {
-	return map[string][]string{
-		"images":  collect.Images(),
-		"styles":  collect.Styles(),
-		"scripts": collect.Scripts(),
-		"video":   collect.Video(),
-		"audio":   collect.Audio(),
+// Assets returns all available and supported by us assets, including
+// the ones of the inline CSS, resolved and classified if the page url is known
+func (collect Collect) Assets() map[string][]*Asset {
+	assets := map[string][]*Asset{
+		"images":  collect.candidates(),
+		"styles":  append(collect.resolve(collect.Styles()), collect.inline("style")...),
+		"scripts": append(collect.resolve(collect.Scripts()), collect.inline("script:not([src])")...),
+		"video":   collect.resolve(collect.Video()),
+		"audio":   collect.resolve(collect.Audio()),
+		"fonts":   nil,
+	}
+
+	for _, reference := range collect.CSS() {
+		assets[reference.Kind] = append(assets[reference.Kind], collect.Resolve(reference.URL))
+	}
+
+	return assets
+}
+
+// resolve the links against the base and classify them
+func (collect Collect) resolve(links []string) (assets []*Asset) {
+	for _, link := range links {
+		assets = append(assets, collect.Resolve(link))
+	}
+
+	return
+}
+
+// candidates resolves the image candidates and keeps their descriptors
+func (collect Collect) candidates() (assets []*Asset) {
+	for _, candidate := range collect.Candidates() {
+		asset := collect.Resolve(candidate.URL)
+		asset.Descriptor = candidate.Descriptor
+		asset.Sizes = candidate.Sizes
+
+		assets = append(assets, asset)
 	}
+
+	return
+}
+
+// Resolve the link against the base and classify it
+func (collect Collect) Resolve(link string) *Asset {
+	return collect.resolveAgainst(collect.base, link)
+}
+
+// ResolveFrom resolves the link against the other document, like the stylesheet,
+// and classifies it by the page url
+func (collect Collect) ResolveFrom(document, link string) *Asset {
+	base, err := url.Parse(document)
+	if err != nil || collect.page == nil {
+		return collect.Resolve(link)
+	}
+
+	return collect.resolveAgainst(base, link)
+}
+
+// resolveAgainst resolves the link against the base and classifies it
+func (collect Collect) resolveAgainst(base *url.URL, link string) *Asset {
+	link = strings.TrimSpace(link)
+
+	if strings.HasPrefix(strings.ToLower(link), "data:") {
+		return &Asset{URL: link, Type: AssetData}
+	}
+
+	if base == nil {
+		return &Asset{URL: link}
+	}
+
+	resolved, err := base.Parse(link)
+	if err != nil {
+		return &Asset{URL: link, Type: AssetExternal}
+	}
+
+	asset := &Asset{URL: resolved.String(), Type: AssetExternal}
+	if resolved.Host == collect.page.Host {
+		asset.Type = AssetInternal
+	}
+
+	return asset
+}
+
+// inline assets of the elements with the content
+func (collect Collect) inline(query string) (assets []*Asset) {
+	collect.doc.Find(query).Each(func(i int, node *goquery.Selection) {
+		if len(strings.TrimSpace(node.Text())) == 0 {
+			return
+		}
+
+		assets = append(assets, &Asset{
+			Type:     AssetInline,
+			Selector: selector(node),
+		})
+	})
+
+	return
+}
+
+// hasRel checks if the "rel" attribute of the element has the value
+func hasRel(node *goquery.Selection, value string) bool {
+	rel, _ := node.Attr("rel")
+
+	for _, item := range strings.Fields(rel) {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selector builds unique CSS selector of the element
+func selector(node *goquery.Selection) string {
+	path := []string{}
+
+	for ; node.Length() > 0 && goquery.NodeName(node) != "html"; node = node.Parent() {
+		name := goquery.NodeName(node)
+
+		id, exist := node.Attr("id")
+		if exist && len(id) > 0 {
+			path = append([]string{name + "#" + id}, path...)
+			break
+		}
+
+		// There is only one of them anyway
+		if name == "body" || name == "head" {
+			path = append([]string{name}, path...)
+			continue
+		}
+
+		position := node.PrevAll().Length() + 1
+		path = append([]string{name + ":nth-child(" + strconv.Itoa(position) + ")"}, path...)
+	}
+
+	return strings.Join(path, " > ")
 }
@@ -1,6 +1,7 @@
 package collect_test
 
 import (
+	"encoding/json"
 	"io/ioutil"
 
 	"github.com/gocolly/colly"
@@ -40,12 +41,137 @@ var _ = Describe("io", func() {
 		})
 	})
 
+	Describe("Candidates", func() {
+		BeforeEach(func() {
+			doc, _ := io.MakeDoc([]byte(`<!doctype html>
+				<img src="a.png" srcset="a.png 1x, a@2x.png 2x" sizes="50vw">
+				<img data-src="lazy.png" data-srcset="lazy-480.png 480w,lazy-800.png 800w">
+				<img src="">
+				<picture>
+					<source srcset="b.webp" type="image/webp" sizes="(max-width: 600px) 100vw">
+					<source data-srcset="c.avif 1.5x">
+					<img src="b.png">
+				</picture>
+				<video><source srcset="nope.png"></video>
+			`))
+
+			data = New(doc)
+		})
+
+		It("Gets candidates with their descriptors", func() {
+			Expect(data.Candidates()).To(Equal([]*Candidate{
+				{URL: "a.png", Sizes: "50vw"},
+				{URL: "a@2x.png", Descriptor: "2x", Sizes: "50vw"},
+				{URL: "lazy.png"},
+				{URL: "lazy-480.png", Descriptor: "480w"},
+				{URL: "lazy-800.png", Descriptor: "800w"},
+				{URL: "b.webp", Sizes: "(max-width: 600px) 100vw"},
+				{URL: "c.avif", Descriptor: "1.5x"},
+				{URL: "b.png"},
+			}))
+		})
+
+		It("Gets images of the candidates", func() {
+			Expect(data.Images()).To(Equal([]string{
+				"a.png",
+				"a@2x.png",
+				"lazy.png",
+				"lazy-480.png",
+				"lazy-800.png",
+				"b.webp",
+				"c.avif",
+				"b.png",
+			}))
+		})
+	})
+
+	Describe("ParseSrcset", func() {
+		It("Parses srcset", func() {
+			Expect(ParseSrcset("  a.png, b.png 2x ,\n c.png  100w  ,, d.png,")).To(Equal([]*Candidate{
+				{URL: "a.png"},
+				{URL: "b.png", Descriptor: "2x"},
+				{URL: "c.png", Descriptor: "100w"},
+				{URL: "d.png"},
+			}))
+		})
+
+		It("Keeps commas of the urls", func() {
+			Expect(ParseSrcset("data:image/png;base64,iVBO 1x, image.php?a=1,2 2x")).To(Equal([]*Candidate{
+				{URL: "data:image/png;base64,iVBO", Descriptor: "1x"},
+				{URL: "image.php?a=1,2", Descriptor: "2x"},
+			}))
+		})
+
+		It("Parses empty srcset", func() {
+			Expect(ParseSrcset(" ")).To(BeNil())
+		})
+	})
+
 	Describe("Styles", func() {
 		It("Gets images", func() {
 			Expect(data.Styles()[0]).To(Equal("test.css"))
 		})
 	})
 
+	Describe("Relations", func() {
+		BeforeEach(func() {
+			doc, _ := io.MakeDoc([]byte(`<!doctype html>
+				<head>
+					<link rel="stylesheet" href="main.css">
+					<link rel="alternate stylesheet" href="dark.css" title="Dark">
+					<link rel="canonical" href="/page">
+					<link rel="canonical" href="/other">
+					<link rel="alternate" hreflang="de" href="https://example.de/page">
+					<link rel="alternate" media="only screen and (max-width: 640px)" href="https://m.example.com/page">
+					<link rel="alternate" type="application/rss+xml" title=" News " href="/feed.xml">
+					<link rel="alternate" type="Application/Atom+XML" href="/atom.xml">
+					<link rel="alternate" type="application/feed+json" href="/feed.json">
+					<link rel="alternate" type="application/json" href="/wp-json/wp/v2/pages/2">
+					<link rel="shortcut icon" href="/favicon.ico">
+					<link rel="apple-touch-icon" sizes="180x180" href="/touch.png">
+					<link rel="manifest" href="/site.webmanifest">
+					<link rel="preload" as="font" href="/font.woff2">
+					<link rel="dns-prefetch" href="//cdn.example.net">
+					<link rel="modulepreload" href="/app.mjs">
+					<link rel="author" href="/humans.txt">
+					<link rel="icon">
+				</head>
+			`))
+
+			data = NewWithURL(doc, "https://example.com/docs/")
+		})
+
+		It("Gets only stylesheets as styles", func() {
+			Expect(data.Styles()).To(Equal([]string{"main.css", "dark.css"}))
+		})
+
+		It("Gets the links by their rel", func() {
+			Expect(data.Relations()).To(Equal(&Relations{
+				Canonical: "https://example.com/page",
+				Alternates: []*Alternate{
+					{URL: "https://example.de/page", Language: "de"},
+					{URL: "https://m.example.com/page", Media: "only screen and (max-width: 640px)"},
+					{URL: "https://example.com/wp-json/wp/v2/pages/2", Type: "application/json"},
+				},
+				Icons: []*Icon{
+					{URL: "https://example.com/favicon.ico", Rel: "shortcut icon"},
+					{URL: "https://example.com/touch.png", Rel: "apple-touch-icon", Sizes: "180x180"},
+				},
+				Manifest: "https://example.com/site.webmanifest",
+				Preloads: []*Preload{
+					{URL: "https://example.com/font.woff2", Rel: "preload", As: "font"},
+					{URL: "https://cdn.example.net", Rel: "dns-prefetch"},
+					{URL: "https://example.com/app.mjs", Rel: "modulepreload"},
+				},
+				Feeds: []*Feed{
+					{URL: "https://example.com/feed.xml", Type: "application/rss+xml", Title: "News"},
+					{URL: "https://example.com/atom.xml", Type: "application/atom+xml"},
+					{URL: "https://example.com/feed.json", Type: "application/feed+json"},
+				},
+			}))
+		})
+	})
+
 	Describe("Scripts", func() {
 		It("Gets scripts", func() {
 			Expect(data.Scripts()[0]).To(Equal("test.js"))
@@ -85,30 +211,151 @@ var _ = Describe("io", func() {
 		})
 	})
 
+	Describe("Anchors", func() {
+		It("Gets anchors", func() {
+			request := &colly.Request{}
+			anchors := data.Anchors(request)
+
+			Expect(anchors).To(HaveLen(1))
+			Expect(anchors[0]).To(Equal(&Anchor{
+				URL:      "https://github.com",
+				Text:     "github",
+				Selector: "body > a:nth-child(2)",
+			}))
+		})
+	})
+
 	Describe("Assets", func() {
 		It("Gets assets", func() {
-			expected := `map[string][]string{
-  "audio": []string{
-    "foo.wav",
+			expected := `map[string][]*collect.Asset{
+  "audio": []*collect.Asset{
+    &collect.Asset{
+      URL: "foo.wav",
+      Type: "",
+      Selector: "",
+      Descriptor: "",
+      Sizes: "",
+    },
   },
-  "images": []string{
-    "test.png",
+  "fonts": nil,
+  "images": []*collect.Asset{
+    &collect.Asset{
+      URL: "test.png",
+      Type: "",
+      Selector: "",
+      Descriptor: "",
+      Sizes: "",
+    },
   },
-  "scripts": []string{
-    "test.js",
+  "scripts": []*collect.Asset{
+    &collect.Asset{
+      URL: "test.js",
+      Type: "",
+      Selector: "",
+      Descriptor: "",
+      Sizes: "",
+    },
   },
-  "styles": []string{
-    "test.css",
+  "styles": []*collect.Asset{
+    &collect.Asset{
+      URL: "test.css",
+      Type: "",
+      Selector: "",
+      Descriptor: "",
+      Sizes: "",
+    },
   },
-  "video": []string{
-    "test.gif",
-    "test.webm",
-    "test.mp4",
-    "test.ogv",
+  "video": []*collect.Asset{
+    &collect.Asset{
+      URL: "test.gif",
+      Type: "",
+      Selector: "",
+      Descriptor: "",
+      Sizes: "",
+    },
+    &collect.Asset{
+      URL: "test.webm",
+      Type: "",
+      Selector: "",
+      Descriptor: "",
+      Sizes: "",
+    },
+    &collect.Asset{
+      URL: "test.mp4",
+      Type: "",
+      Selector: "",
+      Descriptor: "",
+      Sizes: "",
+    },
+    &collect.Asset{
+      URL: "test.ogv",
+      Type: "",
+      Selector: "",
+      Descriptor: "",
+      Sizes: "",
+    },
   },
 }`
 
 			Expect(litter.Sdump(data.Assets())).To(Equal(expected))
 		})
+
+		It("Resolves and classifies assets", func() {
+			doc, _ := io.MakeDoc([]byte(`<!doctype html>
+				<head>
+					<base href="/static/">
+					<link rel="stylesheet" href="//cdn.example.net/main.css">
+					<style>body { color: red }</style>
+					<style> </style>
+				</head>
+				<body>
+					<img src="a.png">
+					<img src="/b.png">
+					<img src="DATA:image/png;base64,iVBO">
+					<script src="https://example.com/app.js"></script>
+					<script>alert(1)</script>
+				</body>
+			`))
+
+			assets := NewWithURL(doc, "https://example.com/docs/page").Assets()
+
+			Expect(assets["images"]).To(Equal([]*Asset{
+				{URL: "https://example.com/static/a.png", Type: AssetInternal},
+				{URL: "https://example.com/b.png", Type: AssetInternal},
+				{URL: "DATA:image/png;base64,iVBO", Type: AssetData},
+			}))
+
+			Expect(assets["styles"]).To(Equal([]*Asset{
+				{URL: "https://cdn.example.net/main.css", Type: AssetExternal},
+				{Type: AssetInline, Selector: "head > style:nth-child(3)"},
+			}))
+
+			Expect(assets["scripts"]).To(Equal([]*Asset{
+				{URL: "https://example.com/app.js", Type: AssetInternal},
+				{Type: AssetInline, Selector: "body > script:nth-child(5)"},
+			}))
+		})
+
+		It("Resolves assets against the page without base", func() {
+			doc, _ := io.MakeDoc([]byte(`<img src="a.png"><img src="../b.png">`))
+
+			assets := NewWithURL(doc, "http://example.com/docs/page").Assets()
+
+			Expect(assets["images"]).To(Equal([]*Asset{
+				{URL: "http://example.com/docs/a.png", Type: AssetInternal},
+				{URL: "http://example.com/b.png", Type: AssetInternal},
+			}))
+		})
+
+		It("Loads assets which were saved as strings", func() {
+			assets := []*Asset{}
+			err := json.Unmarshal([]byte(`["a.png", {"url": "b.png", "type": "data"}]`), &assets)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(assets).To(Equal([]*Asset{
+				{URL: "a.png"},
+				{URL: "b.png", Type: AssetData},
+			}))
+		})
 	})
 })
@@ -0,0 +1,117 @@
+package collect
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Reference is the url found in CSS with the kind of the asset,
+// "styles" for @import, "fonts" for the fonts and "images" for the rest
+type Reference struct {
+	URL  string
+	Kind string
+}
+
+var (
+	comments  = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	imports   = regexp.MustCompile(`(?i)@import\s+(?:url\(\s*(?:"([^"]*)"|'([^']*)'|([^)\s"']*))\s*\)|"([^"]*)"|'([^']*)')`)
+	urls      = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^)\s"']*))\s*\)`)
+	fontFaces = regexp.MustCompile(`(?i)@font-face\s*\{[^}]*\}`)
+)
+
+// fonts are the extensions of the font files
+var fonts = map[string]bool{
+	".woff":  true,
+	".woff2": true,
+	".ttf":   true,
+	".otf":   true,
+	".eot":   true,
+}
+
+// ParseCSS finds @import and url() references of the stylesheet,
+// urls are returned as they are written
+func ParseCSS(css string) (references []*Reference) {
+	css = comments.ReplaceAllString(css, "")
+
+	var (
+		imported = imports.FindAllStringSubmatchIndex(css, -1)
+		faces    = fontFaces.FindAllStringIndex(css, -1)
+	)
+
+	within := func(position int, ranges [][]int) bool {
+		for _, item := range ranges {
+			if position >= item[0] && position < item[1] {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	add := func(link, kind string) {
+		link = strings.TrimSpace(link)
+
+		// Fragments refer to the SVG elements of the same document
+		if len(link) == 0 || strings.HasPrefix(link, "#") {
+			return
+		}
+
+		references = append(references, &Reference{URL: link, Kind: kind})
+	}
+
+	for _, match := range imported {
+		add(submatch(css, match), "styles")
+	}
+
+	for _, match := range urls.FindAllStringSubmatchIndex(css, -1) {
+		if within(match[0], imported) {
+			continue
+		}
+
+		link := submatch(css, match)
+
+		kind := "images"
+		if within(match[0], faces) || isFont(link) {
+			kind = "fonts"
+		}
+
+		add(link, kind)
+	}
+
+	return
+}
+
+// CSS returns the references of the <style> elements and "style" attributes
+func (collect Collect) CSS() (references []*Reference) {
+	collect.doc.Find("style").Each(func(i int, node *goquery.Selection) {
+		references = append(references, ParseCSS(node.Text())...)
+	})
+
+	collect.doc.Find("[style]").Each(func(i int, node *goquery.Selection) {
+		style, _ := node.Attr("style")
+		references = append(references, ParseCSS(style)...)
+	})
+
+	return
+}
+
+// submatch returns the first matched group, they are the alternatives
+func submatch(text string, match []int) string {
+	for i := 2; i < len(match); i += 2 {
+		if match[i] >= 0 {
+			return text[match[i]:match[i+1]]
+		}
+	}
+
+	return ""
+}
+
+// isFont checks the extension of the url
+func isFont(link string) bool {
+	link = strings.SplitN(strings.SplitN(link, "#", 2)[0], "?", 2)[0]
+
+	return fonts[strings.ToLower(path.Ext(link))]
+}
@@ -0,0 +1,89 @@
+package collect_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/collect"
+	"github.com/markelog/map/io"
+)
+
+var _ = Describe("css", func() {
+	Describe("ParseCSS", func() {
+		It("Finds the imports, fonts and images", func() {
+			css := `
+				@import "base.css";
+				@import url('print.css') print;
+				/* url(commented.png) */
+				@font-face {
+					font-family: "Sans";
+					src: url(sans.eot?#iefix) format("embedded-opentype"),
+					     url("sans.svg#sans") format("svg");
+				}
+				body { background: url( "bg.png" ) no-repeat; }
+				.icon { background-image: url(data:image/png;base64,iVBO); }
+				.glyph { src: url(/fonts/glyph.woff2?v=1) }
+				.filter { filter: url(#blur); mask: url(); }
+			`
+
+			Expect(ParseCSS(css)).To(Equal([]*Reference{
+				{URL: "base.css", Kind: "styles"},
+				{URL: "print.css", Kind: "styles"},
+				{URL: "sans.eot?#iefix", Kind: "fonts"},
+				{URL: "sans.svg#sans", Kind: "fonts"},
+				{URL: "bg.png", Kind: "images"},
+				{URL: "data:image/png;base64,iVBO", Kind: "images"},
+				{URL: "/fonts/glyph.woff2?v=1", Kind: "fonts"},
+			}))
+		})
+
+		It("Finds nothing in the empty stylesheet", func() {
+			Expect(ParseCSS("")).To(BeNil())
+		})
+	})
+
+	Describe("Assets", func() {
+		It("Adds the references of the inline CSS", func() {
+			doc, _ := io.MakeDoc([]byte(`<!doctype html>
+				<head>
+					<style>
+						@import "/theme.css";
+						@font-face { src: url(font.woff) }
+					</style>
+				</head>
+				<body>
+					<div style="background: url('hero.jpg')"></div>
+				</body>
+			`))
+
+			assets := NewWithURL(doc, "http://example.com/docs/").Assets()
+
+			Expect(assets["styles"]).To(Equal([]*Asset{
+				{Type: AssetInline, Selector: "head > style:nth-child(1)"},
+				{URL: "http://example.com/theme.css", Type: AssetInternal},
+			}))
+			Expect(assets["fonts"]).To(Equal([]*Asset{
+				{URL: "http://example.com/docs/font.woff", Type: AssetInternal},
+			}))
+			Expect(assets["images"]).To(Equal([]*Asset{
+				{URL: "http://example.com/docs/hero.jpg", Type: AssetInternal},
+			}))
+		})
+	})
+
+	Describe("ResolveFrom", func() {
+		It("Resolves against the document and classifies by the page", func() {
+			doc, _ := io.MakeDoc([]byte(`<!doctype html>`))
+			data := NewWithURL(doc, "http://example.com/docs/")
+
+			Expect(data.ResolveFrom("http://cdn.example.net/css/main.css", "../img/bg.png")).To(Equal(&Asset{
+				URL:  "http://cdn.example.net/img/bg.png",
+				Type: AssetExternal,
+			}))
+			Expect(data.ResolveFrom("http://example.com/css/main.css", "bg.png")).To(Equal(&Asset{
+				URL:  "http://example.com/css/bg.png",
+				Type: AssetInternal,
+			}))
+		})
+	})
+})
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/markelog/map/diff"
+	"github.com/markelog/map/load"
+	"github.com/markelog/map/print"
+)
+
+// Format of the diff
+var diffFormat string
+
+// Diff command example
+const diffExample = `
+  Compare the maps made before and after the deploy
+  $ map https://example.com --out=./before.json
+  $ map https://example.com --out=./after.json
+  $ map diff before.json after.json
+
+  Maps might be in json or yaml
+  $ map diff before.yaml after.json
+
+  Comment the pull request with it
+  $ map diff before.json after.json --format=markdown
+`
+
+// DiffCommand config
+var DiffCommand = &cobra.Command{
+	Use:     "diff old.json new.json",
+	Short:   "Compare two maps, exits with 1 if pages were removed or links got broken",
+	Example: diffExample,
+	Run:     Diff,
+}
+
+// Diff the maps
+func Diff(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		print.Error(errors.New("Two maps are needed for the comparison"), 2)
+
+		return
+	}
+
+	old, err := load.Load(args[0])
+	print.Error(err, 2)
+
+	new, err := load.Load(args[1])
+	print.Error(err, 2)
+
+	result := diff.Compare(old, new)
+
+	serialized, err := diff.Format(result, diffFormat)
+	print.Error(err, 2)
+
+	fmt.Println(serialized)
+
+	if result.Regressions() {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	DiffCommand.Flags().StringVarP(
+		&diffFormat,
+		"format",
+		"f",
+		"text",
+		"Format of the diff: "+strings.Join(diff.Formats, ", "),
+	)
+
+	Command.AddCommand(DiffCommand)
+}
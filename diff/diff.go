@@ -0,0 +1,279 @@
+// Package diff compares two maps of the same site
+package diff
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/markelog/map/spider"
+)
+
+// Diff between the old and the new maps
+type Diff struct {
+	Added   []string  `json:"added"`
+	Removed []string  `json:"removed"`
+	Titles  []*Title  `json:"titles"`
+	Broken  []*Broken `json:"broken"`
+	Fixed   []*Broken `json:"fixed"`
+	Assets  []*Assets `json:"assets"`
+	Moved   []*Move   `json:"moved"`
+}
+
+// Title change of the page
+type Title struct {
+	URL string `json:"url"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Broken link which appeared or disappeared
+type Broken struct {
+	URL        string `json:"url"`
+	Page       string `json:"page"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Class      string `json:"class,omitempty"`
+}
+
+// Assets change of the page
+type Assets struct {
+	URL     string   `json:"url"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// Move of the page, none of the pages which linked to it do it anymore,
+// old and new are the first of the linking pages
+type Move struct {
+	URL string `json:"url"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Compare the maps
+func Compare(old, new *spider.Result) *Diff {
+	var (
+		result = &Diff{
+			Added:   []string{},
+			Removed: []string{},
+			Titles:  []*Title{},
+			Broken:  []*Broken{},
+			Fixed:   []*Broken{},
+			Assets:  []*Assets{},
+			Moved:   []*Move{},
+		}
+
+		oldPages, oldOrder = flatten(old)
+		newPages, newOrder = flatten(new)
+
+		oldLinking = linking(oldPages, oldOrder)
+		newLinking = linking(newPages, newOrder)
+	)
+
+	for _, url := range oldOrder {
+		if _, ok := newPages[url]; ok == false {
+			result.Removed = append(result.Removed, url)
+		}
+	}
+
+	for _, url := range newOrder {
+		current := newPages[url]
+
+		previous, ok := oldPages[url]
+		if ok == false {
+			result.Added = append(result.Added, url)
+			continue
+		}
+
+		if previous.Name != current.Name {
+			result.Titles = append(result.Titles, &Title{
+				URL: url,
+				Old: previous.Name,
+				New: current.Name,
+			})
+		}
+
+		// Root doesn't have the parent
+		if len(previous.Parent) > 0 && len(current.Parent) > 0 && moved(oldLinking[url], newLinking[url]) {
+			result.Moved = append(result.Moved, &Move{
+				URL: url,
+				Old: oldLinking[url][0],
+				New: newLinking[url][0],
+			})
+		}
+
+		added, removed := compare(assets(previous.Result), assets(current.Result))
+		if len(added) > 0 || len(removed) > 0 {
+			result.Assets = append(result.Assets, &Assets{
+				URL:     url,
+				Added:   added,
+				Removed: removed,
+			})
+		}
+	}
+
+	oldBroken, oldLinks := broken(oldOrder, oldPages)
+	newBroken, newLinks := broken(newOrder, newPages)
+
+	for _, link := range newLinks {
+		if _, ok := oldBroken[link]; ok == false {
+			result.Broken = append(result.Broken, newBroken[link])
+		}
+	}
+
+	for _, link := range oldLinks {
+		if _, ok := newBroken[link]; ok == false {
+			result.Fixed = append(result.Fixed, oldBroken[link])
+		}
+	}
+
+	return result
+}
+
+// Empty finds out if there is no difference
+func (diff *Diff) Empty() bool {
+	return len(diff.Added) == 0 &&
+		len(diff.Removed) == 0 &&
+		len(diff.Titles) == 0 &&
+		len(diff.Broken) == 0 &&
+		len(diff.Fixed) == 0 &&
+		len(diff.Assets) == 0 &&
+		len(diff.Moved) == 0
+}
+
+// Regressions finds out if the new map is worse,
+// i.e. there are removed pages or new broken links
+func (diff *Diff) Regressions() bool {
+	return len(diff.Removed) > 0 || len(diff.Broken) > 0
+}
+
+// flatten the tree into the pages keyed by their urls
+// and the urls in the order of the walk
+func flatten(data *spider.Result) (map[string]*spider.Page, []string) {
+	var (
+		pages = map[string]*spider.Page{}
+		order = []string{}
+	)
+
+	for _, node := range spider.Flatten(data) {
+		if _, ok := pages[node.URL]; ok == false {
+			pages[node.URL] = node
+			order = append(order, node.URL)
+		}
+	}
+
+	return pages, order
+}
+
+// linking finds the pages which link to every page, sorted, unlike
+// the parent in the tree they don't depend on the crawl order
+func linking(pages map[string]*spider.Page, order []string) map[string][]string {
+	result := map[string][]string{}
+
+	for _, source := range order {
+		known := map[string]bool{}
+
+		for _, link := range pages[source].Links {
+			target := strings.SplitN(link, "#", 2)[0]
+			if target == source || known[target] {
+				continue
+			}
+
+			known[target] = true
+			result[target] = append(result[target], source)
+		}
+	}
+
+	for _, sources := range result {
+		sort.Strings(sources)
+	}
+
+	return result
+}
+
+// moved finds out if none of the old linking pages link to the page anymore
+func moved(old, new []string) bool {
+	if len(old) == 0 || len(new) == 0 {
+		return false
+	}
+
+	for _, source := range new {
+		index := sort.SearchStrings(old, source)
+		if index < len(old) && old[index] == source {
+			return false
+		}
+	}
+
+	return true
+}
+
+// broken links keyed by their urls with the first page they were found on
+func broken(order []string, pages map[string]*spider.Page) (map[string]*Broken, []string) {
+	var (
+		result = map[string]*Broken{}
+		links  = []string{}
+	)
+
+	for _, url := range order {
+		for _, link := range pages[url].Broken {
+			if _, ok := result[link.URL]; ok {
+				continue
+			}
+
+			result[link.URL] = &Broken{
+				URL:        link.URL,
+				Page:       url,
+				StatusCode: link.StatusCode,
+				Class:      link.Class,
+			}
+			links = append(links, link.URL)
+		}
+	}
+
+	return result, links
+}
+
+// assets urls of all the kinds, inline ones don't have them
+func assets(data *spider.Result) (result []string) {
+	for _, list := range data.Assets {
+		for _, asset := range list {
+			if len(asset.URL) > 0 {
+				result = append(result, asset.URL)
+			}
+		}
+	}
+
+	return
+}
+
+// compare the lists, result is sorted
+func compare(old, new []string) (added, removed []string) {
+	var (
+		previous = map[string]bool{}
+		current  = map[string]bool{}
+	)
+
+	for _, item := range old {
+		previous[item] = true
+	}
+
+	for _, item := range new {
+		current[item] = true
+	}
+
+	for item := range current {
+		if previous[item] == false {
+			added = append(added, item)
+		}
+	}
+
+	for item := range previous {
+		if current[item] == false {
+			removed = append(removed, item)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return
+}
@@ -0,0 +1,190 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/markelog/map/collect"
+	. "github.com/markelog/map/diff"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("diff", func() {
+	var old, new *spider.Result
+
+	BeforeEach(func() {
+		old = &spider.Result{
+			URL:   "http://example.com",
+			Name:  "Home",
+			Links: []string{"http://example.com/a", "http://example.com/removed"},
+			Assets: map[string][]*collect.Asset{
+				"styles": {{URL: "http://example.com/a.css"}},
+			},
+			Broken: []*spider.Broken{
+				{URL: "http://example.com/fixed", StatusCode: 404},
+			},
+			Children: []*spider.Result{
+				{
+					URL:   "http://example.com/a",
+					Name:  "A",
+					Links: []string{"http://example.com/b"},
+					Children: []*spider.Result{
+						{URL: "http://example.com/b", Name: "B"},
+					},
+				},
+				{URL: "http://example.com/removed"},
+			},
+		}
+
+		new = &spider.Result{
+			URL:  "http://example.com",
+			Name: "Home",
+			Links: []string{
+				"http://example.com/a",
+				"http://example.com/b#top",
+				"http://example.com/added",
+			},
+			Assets: map[string][]*collect.Asset{
+				"styles":  {{URL: "http://example.com/b.css"}},
+				"scripts": {},
+			},
+			Broken: []*spider.Broken{
+				{URL: "http://nope.example", Class: spider.ClassDNS},
+			},
+			Children: []*spider.Result{
+				{URL: "http://example.com/a", Name: "A!"},
+				{URL: "http://example.com/b", Name: "B"},
+				{URL: "http://example.com/added"},
+			},
+		}
+	})
+
+	Describe("Compare", func() {
+		It("Finds the differences", func() {
+			result := Compare(old, new)
+
+			Expect(result.Added).To(Equal([]string{"http://example.com/added"}))
+			Expect(result.Removed).To(Equal([]string{"http://example.com/removed"}))
+			Expect(result.Titles).To(Equal([]*Title{
+				{URL: "http://example.com/a", Old: "A", New: "A!"},
+			}))
+			Expect(result.Broken).To(Equal([]*Broken{
+				{URL: "http://nope.example", Page: "http://example.com", Class: spider.ClassDNS},
+			}))
+			Expect(result.Fixed).To(Equal([]*Broken{
+				{URL: "http://example.com/fixed", Page: "http://example.com", StatusCode: 404},
+			}))
+			Expect(result.Assets).To(Equal([]*Assets{
+				{
+					URL:     "http://example.com",
+					Added:   []string{"http://example.com/b.css"},
+					Removed: []string{"http://example.com/a.css"},
+				},
+			}))
+			Expect(result.Moved).To(Equal([]*Move{
+				{URL: "http://example.com/b", Old: "http://example.com/a", New: "http://example.com"},
+			}))
+			Expect(result.Regressions()).To(Equal(true))
+			Expect(result.Empty()).To(Equal(false))
+		})
+
+		It("Finds nothing in the same maps", func() {
+			result := Compare(old, old)
+
+			Expect(result.Empty()).To(Equal(true))
+			Expect(result.Regressions()).To(Equal(false))
+		})
+
+		It("Does not consider new pages as regression", func() {
+			old.Broken = nil
+			result := Compare(&spider.Result{URL: "http://example.com"}, old)
+
+			Expect(result.Regressions()).To(Equal(false))
+		})
+
+		It("Does not find moves in the same site crawled in the other order", func() {
+			links := func(urls ...string) []string { return urls }
+
+			// Both "/a" and "/c" link to "/b", it's the child of whichever was crawled first
+			crawl := func(first, second string) *spider.Result {
+				return &spider.Result{
+					URL:   "http://example.com",
+					Links: links("http://example.com/a", "http://example.com/c"),
+					Children: []*spider.Result{
+						{
+							URL:      first,
+							Links:    links("http://example.com/b"),
+							Children: []*spider.Result{{URL: "http://example.com/b"}},
+						},
+						{URL: second, Links: links("http://example.com/b")},
+					},
+				}
+			}
+
+			result := Compare(
+				crawl("http://example.com/a", "http://example.com/c"),
+				crawl("http://example.com/c", "http://example.com/a"),
+			)
+
+			Expect(result.Moved).To(BeEmpty())
+			Expect(result.Empty()).To(Equal(true))
+		})
+	})
+
+	Describe("Format", func() {
+		It("Formats as text", func() {
+			result, err := Format(Compare(old, new), "text")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(`Added pages (1)
+  + http://example.com/added
+
+Removed pages (1)
+  - http://example.com/removed
+
+Changed titles (1)
+  ~ http://example.com/a: "A" -> "A!"
+
+New broken links (1)
+  ! http://nope.example (dns) on http://example.com
+
+Fixed broken links (1)
+  + http://example.com/fixed
+
+Changed assets (1)
+  ~ http://example.com
+      + http://example.com/b.css
+      - http://example.com/a.css
+
+Moved pages (1)
+  ~ http://example.com/b: http://example.com/a -> http://example.com`))
+		})
+
+		It("Formats as markdown", func() {
+			result, _ := Format(Compare(old, new), "markdown")
+
+			Expect(result).To(HavePrefix("### Added pages (1)\n\n- <http://example.com/added>\n\n### Removed pages (1)"))
+			Expect(result).To(ContainSubstring("  - added <http://example.com/b.css>\n"))
+		})
+
+		It("Formats as json", func() {
+			result, _ := Format(Compare(old, old), "json")
+
+			Expect(result).To(Equal(
+				`{"added":[],"removed":[],"titles":[],"broken":[],"fixed":[],"assets":[],"moved":[]}`,
+			))
+		})
+
+		It("Tells there is no changes", func() {
+			result, _ := Format(Compare(old, old), "text")
+
+			Expect(result).To(Equal("No changes"))
+		})
+
+		It("Returns error for unknown format", func() {
+			_, err := Format(Compare(old, old), "nope")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,198 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/go-errors/errors"
+)
+
+// Available formats of the diff
+var Formats = []string{"text", "json", "markdown"}
+
+// Format the diff, format is one of the Formats
+func Format(diff *Diff, format string) (string, error) {
+	switch format {
+	case "text", "":
+		return Text(diff), nil
+	case "json":
+		return JSON(diff)
+	case "markdown":
+		return Markdown(diff), nil
+	}
+
+	return "", errors.New(`Unknown "` + format + `" format, should be either "text", "json" or "markdown"`)
+}
+
+// JSON representation of the diff
+func JSON(diff *Diff) (string, error) {
+	result, err := json.Marshal(diff)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	return string(result), nil
+}
+
+// Text representation of the diff
+func Text(diff *Diff) string {
+	if diff.Empty() {
+		return "No changes"
+	}
+
+	buffer := &bytes.Buffer{}
+
+	section := func(title string, count int) {
+		if buffer.Len() > 0 {
+			buffer.WriteString("\n")
+		}
+
+		fmt.Fprintf(buffer, "%s (%d)\n", title, count)
+	}
+
+	if len(diff.Added) > 0 {
+		section("Added pages", len(diff.Added))
+		for _, url := range diff.Added {
+			fmt.Fprintf(buffer, "  + %s\n", url)
+		}
+	}
+
+	if len(diff.Removed) > 0 {
+		section("Removed pages", len(diff.Removed))
+		for _, url := range diff.Removed {
+			fmt.Fprintf(buffer, "  - %s\n", url)
+		}
+	}
+
+	if len(diff.Titles) > 0 {
+		section("Changed titles", len(diff.Titles))
+		for _, title := range diff.Titles {
+			fmt.Fprintf(buffer, "  ~ %s: %q -> %q\n", title.URL, title.Old, title.New)
+		}
+	}
+
+	if len(diff.Broken) > 0 {
+		section("New broken links", len(diff.Broken))
+		for _, link := range diff.Broken {
+			fmt.Fprintf(buffer, "  ! %s (%s) on %s\n", link.URL, reason(link), link.Page)
+		}
+	}
+
+	if len(diff.Fixed) > 0 {
+		section("Fixed broken links", len(diff.Fixed))
+		for _, link := range diff.Fixed {
+			fmt.Fprintf(buffer, "  + %s\n", link.URL)
+		}
+	}
+
+	if len(diff.Assets) > 0 {
+		section("Changed assets", len(diff.Assets))
+		for _, assets := range diff.Assets {
+			fmt.Fprintf(buffer, "  ~ %s\n", assets.URL)
+
+			for _, url := range assets.Added {
+				fmt.Fprintf(buffer, "      + %s\n", url)
+			}
+
+			for _, url := range assets.Removed {
+				fmt.Fprintf(buffer, "      - %s\n", url)
+			}
+		}
+	}
+
+	if len(diff.Moved) > 0 {
+		section("Moved pages", len(diff.Moved))
+		for _, move := range diff.Moved {
+			fmt.Fprintf(buffer, "  ~ %s: %s -> %s\n", move.URL, move.Old, move.New)
+		}
+	}
+
+	return string(bytes.TrimSuffix(buffer.Bytes(), []byte("\n")))
+}
+
+// Markdown representation of the diff
+func Markdown(diff *Diff) string {
+	if diff.Empty() {
+		return "No changes"
+	}
+
+	buffer := &bytes.Buffer{}
+
+	section := func(title string, count int) {
+		if buffer.Len() > 0 {
+			buffer.WriteString("\n")
+		}
+
+		fmt.Fprintf(buffer, "### %s (%d)\n\n", title, count)
+	}
+
+	if len(diff.Added) > 0 {
+		section("Added pages", len(diff.Added))
+		for _, url := range diff.Added {
+			fmt.Fprintf(buffer, "- <%s>\n", url)
+		}
+	}
+
+	if len(diff.Removed) > 0 {
+		section("Removed pages", len(diff.Removed))
+		for _, url := range diff.Removed {
+			fmt.Fprintf(buffer, "- <%s>\n", url)
+		}
+	}
+
+	if len(diff.Titles) > 0 {
+		section("Changed titles", len(diff.Titles))
+		for _, title := range diff.Titles {
+			fmt.Fprintf(buffer, "- <%s>: `%s` → `%s`\n", title.URL, title.Old, title.New)
+		}
+	}
+
+	if len(diff.Broken) > 0 {
+		section("New broken links", len(diff.Broken))
+		for _, link := range diff.Broken {
+			fmt.Fprintf(buffer, "- <%s> (%s) on <%s>\n", link.URL, reason(link), link.Page)
+		}
+	}
+
+	if len(diff.Fixed) > 0 {
+		section("Fixed broken links", len(diff.Fixed))
+		for _, link := range diff.Fixed {
+			fmt.Fprintf(buffer, "- <%s>\n", link.URL)
+		}
+	}
+
+	if len(diff.Assets) > 0 {
+		section("Changed assets", len(diff.Assets))
+		for _, assets := range diff.Assets {
+			fmt.Fprintf(buffer, "- <%s>\n", assets.URL)
+
+			for _, url := range assets.Added {
+				fmt.Fprintf(buffer, "  - added <%s>\n", url)
+			}
+
+			for _, url := range assets.Removed {
+				fmt.Fprintf(buffer, "  - removed <%s>\n", url)
+			}
+		}
+	}
+
+	if len(diff.Moved) > 0 {
+		section("Moved pages", len(diff.Moved))
+		for _, move := range diff.Moved {
+			fmt.Fprintf(buffer, "- <%s>: <%s> → <%s>\n", move.URL, move.Old, move.New)
+		}
+	}
+
+	return string(bytes.TrimSuffix(buffer.Bytes(), []byte("\n")))
+}
+
+// reason why the link is broken
+func reason(link *Broken) string {
+	if link.StatusCode != 0 {
+		return strconv.Itoa(link.StatusCode)
+	}
+
+	return link.Class
+}
@@ -3,6 +3,7 @@ package io
 
 import (
 	"io/ioutil"
+	"os"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -22,6 +23,17 @@ func WriteFile(path, content string) (err error) {
 	return nil
 }
 
+// CreateFile creates or truncates the file for writing,
+// with the same permissions as WriteFile
+func CreateFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0700)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	return file, nil
+}
+
 // MakeDoc creates a HTML document from the bytes
 func MakeDoc(body []byte) (*goquery.Document, error) {
 	text := strings.NewReader(string(body))
@@ -70,6 +70,28 @@ var _ = Describe("io", func() {
 		})
 	})
 
+	Describe("CreateFile", func() {
+		It("Creates the file", func() {
+			dir, _ := ioutil.TempDir("", "map")
+			defer os.RemoveAll(dir)
+
+			file, err := CreateFile(dir + "/test")
+			Expect(err).To(BeNil())
+
+			file.WriteString("test")
+			file.Close()
+
+			data, _ := ioutil.ReadFile(dir + "/test")
+			Expect(string(data)).To(Equal("test"))
+		})
+
+		It("Correctly returns an error", func() {
+			_, err := CreateFile("/nope/nope/nope")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("MakeDoc", func() {
 		It("Correctly parses the document", func() {
 			var (
@@ -0,0 +1,84 @@
+// Package load provides methods to load previously saved maps
+package load
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-errors/errors"
+
+	"github.com/markelog/map/spider"
+)
+
+// Formats of the maps
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Load the map saved by json or yaml reporter,
+// "-" path reads it from the standard input
+func Load(path string) (*spider.Result, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	return Parse(data, Detect(path, data))
+}
+
+// Parse the map in provided format
+func Parse(data []byte, format string) (*spider.Result, error) {
+	var (
+		result = &spider.Result{}
+		err    error
+	)
+
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, result)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, result)
+	default:
+		return nil, errors.New(`Unknown "` + format + `" format, should be either "json" or "yaml"`)
+	}
+
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	result.RestoreParents()
+
+	return result, nil
+}
+
+// Detect the format by the extension of the file or by its content
+func Detect(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return FormatJSON
+	}
+
+	return FormatYAML
+}
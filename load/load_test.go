@@ -0,0 +1,138 @@
+package load_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/markelog/map/collect"
+	. "github.com/markelog/map/load"
+	"github.com/markelog/map/reporters/json"
+	"github.com/markelog/map/reporters/yaml"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("load", func() {
+	var (
+		data *spider.Result
+		dir  string
+	)
+
+	BeforeEach(func() {
+		data = &spider.Result{
+			URL:  "http://example.com",
+			Name: "Home",
+			Assets: map[string][]*collect.Asset{
+				"styles": {{URL: "http://example.com/main.css"}},
+			},
+			Links: []string{"http://example.com/a"},
+			Broken: []*spider.Broken{
+				{URL: "http://example.com/nope", StatusCode: 404, Class: spider.ClassHTTP},
+			},
+			Children: []*spider.Result{
+				{
+					URL:  "http://example.com/a",
+					Name: "A",
+					Children: []*spider.Result{
+						{URL: "http://example.com/b", Name: "B"},
+					},
+				},
+			},
+		}
+
+		dir, _ = ioutil.TempDir("", "map")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	save := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		ioutil.WriteFile(path, []byte(content), 0700)
+
+		return path
+	}
+
+	Describe("Load", func() {
+		It("Loads json", func() {
+			serialized, _ := json.Execute(data)
+
+			result, err := Load(save("map.json", serialized))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Name).To(Equal("Home"))
+			Expect(result.Assets).To(Equal(data.Assets))
+			Expect(result.Broken).To(Equal(data.Broken))
+			Expect(result.Children[0].Children[0].URL).To(Equal("http://example.com/b"))
+		})
+
+		It("Loads yaml", func() {
+			serialized, _ := yaml.Execute(data)
+
+			result, err := Load(save("map.yml", serialized))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Name).To(Equal("Home"))
+			Expect(result.Broken).To(Equal(data.Broken))
+			Expect(result.Children[0].Children[0].URL).To(Equal("http://example.com/b"))
+		})
+
+		It("Restores the parents", func() {
+			serialized, _ := json.Execute(data)
+
+			result, _ := Load(save("map", serialized))
+			last := result.Children[0].Children[0]
+
+			Expect(result.Parent()).To(BeNil())
+			Expect(last.Parent()).To(Equal(result.Children[0]))
+			Expect(last.Depth()).To(Equal(2))
+		})
+
+		It("Loads the map saved when broken links and assets were the strings", func() {
+			result, err := Load("testdata/old.json")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Broken).To(Equal([]*spider.Broken{{URL: "http://example.com/nope"}}))
+			Expect(result.Assets["styles"]).To(Equal([]*collect.Asset{{URL: "http://example.com/main.css"}}))
+			Expect(result.Children[0].Broken).To(Equal([]*spider.Broken{{URL: "http://nope.example"}}))
+			Expect(result.Children[0].Parent()).To(Equal(result))
+		})
+
+		It("Returns error for absent file", func() {
+			_, err := Load(filepath.Join(dir, "nope.json"))
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Returns error for malformed map", func() {
+			_, err := Load(save("map.json", "{nope"))
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Detect", func() {
+		It("Detects by the extension", func() {
+			Expect(Detect("map.JSON", []byte("url: nope"))).To(Equal(FormatJSON))
+			Expect(Detect("map.yaml", []byte("{}"))).To(Equal(FormatYAML))
+			Expect(Detect("map.yml", []byte("{}"))).To(Equal(FormatYAML))
+		})
+
+		It("Detects by the content", func() {
+			Expect(Detect("map", []byte("\n  {\"url\": \"nope\"}"))).To(Equal(FormatJSON))
+			Expect(Detect("-", []byte("url: nope"))).To(Equal(FormatYAML))
+		})
+	})
+
+	Describe("Parse", func() {
+		It("Returns error for unknown format", func() {
+			_, err := Parse([]byte("{}"), "xml")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -2,11 +2,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/markelog/map/io"
 	"github.com/markelog/map/print"
@@ -23,6 +30,63 @@ var out string
 // Domains to follow
 var domains string
 
+// Depth limit of the crawl
+var depth int
+
+// Pages limit of the crawl
+var maxPages int
+
+// Parallel requests limit
+var parallel int
+
+// Parallel requests limit for the same domain
+var domainParallel int
+
+// Pause between requests to the same domain
+var delay time.Duration
+
+// Random pause between requests to the same domain
+var randomDelay time.Duration
+
+// Do not respect robots.txt
+var ignoreRobots bool
+
+// Sitemap to seed the crawl with
+var sitemap string
+
+// Collect graph of all the links
+var graph bool
+
+// Request the assets and record the broken ones
+var checkAssets bool
+
+// Do not request the stylesheets for their references
+var noStylesheets bool
+
+// Cluster pages of the dot reporter
+var cluster string
+
+// Rows of the csv and tsv reporters
+var mode string
+
+// Columns of the csv and tsv reporters
+var columns []string
+
+// Template file of the template reporter
+var templateFile string
+
+// Show asset counts in the tree and markdown reporters
+var showAssets bool
+
+// Show broken links in the tree and markdown reporters
+var showBroken bool
+
+// Draw the tree without unicode characters
+var asciiTree bool
+
+// discoverSitemap is the "sitemap" flag value when it has no value
+const discoverSitemap = "robots.txt"
+
 // Command example
 const example = `
   Create map and output it to the terminal
@@ -39,6 +103,56 @@ const example = `
 
 	With additional domains
 	$ map https://example.com --domains=www.google.ru,www.google.com
+
+  Go no deeper than two levels from the root and request no more than 100 pages
+  $ map https://example.com --depth=2 --max-pages=100
+
+  Be polite: two requests at the time, with a second between them
+  $ map https://example.com --domain-parallel=2 --delay=1s --random-delay=500ms
+
+  Crawl your own staging site regardless of its robots.txt
+  $ map https://staging.example.com --ignore-robots
+
+  Seed the crawl with the sitemaps from robots.txt (or /sitemap.xml) and compare them with the links
+  $ map https://example.com --sitemap
+
+  Or with the specific sitemap
+  $ map https://example.com --sitemap=https://example.com/sitemap-index.xml.gz
+
+  Besides the tree, output graph of all the links between the pages
+  $ map https://example.com --graph
+
+  Find missing images, scripts and styles as well
+  $ map https://example.com --check-assets
+
+  Draw the site structure with Graphviz, pages are clustered by the first segment of their path
+  $ map https://example.com -r dot --cluster=path | dot -Tsvg > example.com.svg
+
+  Standalone HTML report with the tree, list of the pages and broken links
+  $ map https://example.com -r html --out=./report.html
+
+  Spreadsheet friendly list of the pages or of the links between them
+  $ map https://example.com -r csv --columns=url,title,status,broken
+  $ map https://example.com -r tsv --mode=edges --graph
+
+  Write the pages while they are crawled, one json object per line
+  $ map https://example.com -r ndjson | jq .url
+
+  Render your own format with Go template
+  $ map https://example.com -r template --template=./rss.tmpl
+
+  Draw the tree like the "tree" command or nested markdown lists, with the broken links
+  $ map https://example.com -r tree --show-broken --show-assets
+  $ map https://example.com -r markdown --show-broken
+
+  Generate sitemap.xml, big maps are split into sitemap-N.xml files placed next to it
+  $ map https://example.com -r sitemap --out=./sitemap.xml
+
+  Compare two maps, see "map diff --help"
+  $ map diff before.json after.json
+
+  Check the links for the CI, see "map check --help"
+  $ map check https://example.com --junit=./map.xml
 `
 
 // Command config
@@ -46,6 +160,7 @@ var Command = &cobra.Command{
 	Use:     "map https://example.com",
 	Short:   "Site mapper",
 	Example: example,
+	Args:    cobra.ArbitraryArgs,
 	Run:     Run,
 }
 
@@ -65,21 +180,56 @@ func Run(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	crawler := spider.New(args[0], domains)
+	crawler := newCrawler(args[0])
 
-	// Validate the input
-	print.Error(crawler.Validate(), 2)
+	// Stop the crawl on interrupt, but still report what we got so far
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go interrupt(cancel)
+
+	reporting := reporters.Options{
+		Cluster:  cluster,
+		Mode:     mode,
+		Columns:  columns,
+		Template: templateFile,
+
+		ShowAssets: showAssets,
+		ShowBroken: showBroken,
+		ASCII:      asciiTree,
+	}
 
 	// Crawl the site, show the spinner and determine the exit code
-	exitCode := print.Spin(crawler.Crawl())
+	exitCode := crawl(crawler.CrawlContext(ctx), reporting)
+	if ctx.Err() != nil && exitCode == 0 {
+		exitCode = 130
+	}
 
-	// Get the result and send it to the reporter
+	// Streaming reporter already wrote everything
+	if reporters.Streams(reporter) {
+		os.Exit(exitCode)
+	}
+
+	// Get the result and send it to the reporter,
+	// error of the root was already shown by the spinner
 	data, err := crawler.Get()
+	if err != nil {
+		os.Exit(1)
+	}
+
+	serialized, err := reporters.Execute(reporter, data, reporting)
 	print.Error(err, 1)
 
-	serialized, err := reporters.Execute(reporter, data)
+	// Some reporters need more than one file, put them next to the output
+	files, err := reporters.Files(reporter, data, reporting)
 	print.Error(err, 1)
 
+	if len(files) > 0 && len(out) == 0 {
+		err := errors.New(`Reporter "` + reporter + `" needs more than one file, use "out" flag to write them`)
+		print.Error(err, 2)
+
+		return
+	}
+
 	if len(serialized) > 0 {
 		// Either print to the console or save it to a file
 		if len(out) == 0 {
@@ -89,21 +239,86 @@ func Run(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	for name, content := range files {
+		print.Error(io.WriteFile(filepath.Join(filepath.Dir(out), name), content), 1)
+	}
+
 	os.Exit(exitCode)
 }
 
+// newCrawler makes the crawler configured by the flags
+func newCrawler(target string, extra ...spider.Option) *spider.Spider {
+	options := []spider.Option{
+		spider.WithDomains(strings.Split(domains, ",")...),
+		spider.WithMaxDepth(depth),
+		spider.WithMaxPages(maxPages),
+		spider.WithConcurrency(parallel),
+		spider.WithDomainConcurrency(domainParallel),
+		spider.WithDelay(delay),
+		spider.WithRandomDelay(randomDelay),
+		spider.WithIgnoreRobots(ignoreRobots),
+		spider.WithGraph(graph),
+		spider.WithCheckAssets(checkAssets),
+		spider.WithStylesheets(noStylesheets == false),
+	}
+
+	if sitemap == discoverSitemap {
+		options = append(options, spider.WithSitemap())
+	} else if len(sitemap) > 0 {
+		options = append(options, spider.WithSitemap(sitemap))
+	}
+
+	crawler := spider.NewWithOptions(target, append(options, extra...)...)
+
+	// Validate the input
+	print.Error(crawler.Validate(), 2)
+
+	return crawler
+}
+
+// crawl waits for the crawl to finish, streaming reporter
+// writes the pages while they come, spinner is not shown
+// if they are written to the console
+func crawl(progress chan *spider.Progress, options reporters.Options) int {
+	if reporters.Streams(reporter) == false {
+		return print.Spin(progress)
+	}
+
+	if len(out) == 0 {
+		return print.Wait(reporters.Stream(reporter, progress, os.Stdout, options))
+	}
+
+	file, err := io.CreateFile(out)
+	print.Error(err, 1)
+	defer file.Close()
+
+	return print.Spin(reporters.Stream(reporter, progress, file, options))
+}
+
+// interrupt cancels the crawl on the first SIGINT or SIGTERM,
+// the second one terminates the process as usual
+func interrupt(cancel context.CancelFunc) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	<-signals
+	signal.Stop(signals)
+
+	cancel()
+}
+
 // Init
 func init() {
 	cobra.OnInitialize()
 
-	flags := Command.PersistentFlags()
+	flags := Command.Flags()
 
 	flags.StringVarP(
 		&reporter,
 		"reporter",
 		"r",
 		"json",
-		"Show data in certain representation",
+		"Show data in certain representation: "+strings.Join(reporters.Names(), ", "),
 	)
 
 	flags.StringVarP(
@@ -114,6 +329,60 @@ func init() {
 		"Output data to the file without pipe but with the spinner :)",
 	)
 
+	crawlFlags(flags)
+
+	flags.StringVar(
+		&cluster,
+		"cluster",
+		"",
+		`Cluster pages of the dot reporter by "path" or "domain"`,
+	)
+
+	flags.StringVar(
+		&mode,
+		"mode",
+		"pages",
+		`Rows of the csv and tsv reporters, one per "pages" or per link with "edges"`,
+	)
+
+	flags.StringSliceVar(
+		&columns,
+		"columns",
+		[]string{},
+		"Columns of the csv and tsv reporters, all of them by default",
+	)
+
+	flags.StringVar(
+		&templateFile,
+		"template",
+		"",
+		"Go text/template file of the template reporter",
+	)
+
+	flags.BoolVar(
+		&showAssets,
+		"show-assets",
+		false,
+		"Show asset counts of the pages in the tree and markdown reporters",
+	)
+
+	flags.BoolVar(
+		&showBroken,
+		"show-broken",
+		false,
+		"Show broken links of the pages in the tree and markdown reporters",
+	)
+
+	flags.BoolVar(
+		&asciiTree,
+		"ascii",
+		false,
+		"Draw the tree reporter without unicode characters",
+	)
+}
+
+// crawlFlags defines the flags of the crawl, they are shared between the commands
+func crawlFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(
 		&domains,
 		"domains",
@@ -121,6 +390,84 @@ func init() {
 		"",
 		"Domains to follow (as addition to the base url), comma as a delimter",
 	)
+
+	flags.IntVar(
+		&depth,
+		"depth",
+		0,
+		"How deep to go from the base url, 0 means no limit",
+	)
+
+	flags.IntVar(
+		&maxPages,
+		"max-pages",
+		0,
+		"How many pages to request, 0 means no limit",
+	)
+
+	flags.IntVar(
+		&parallel,
+		"parallel",
+		0,
+		"How many requests to make at the same time, 0 means no limit",
+	)
+
+	flags.IntVar(
+		&domainParallel,
+		"domain-parallel",
+		0,
+		"How many requests to make to the same domain at the same time, 0 means no limit (or 1 with delay)",
+	)
+
+	flags.DurationVar(
+		&delay,
+		"delay",
+		0,
+		"Pause between requests to the same domain, like 500ms or 2s",
+	)
+
+	flags.DurationVar(
+		&randomDelay,
+		"random-delay",
+		0,
+		"Additional random pause, up to the value, between requests to the same domain",
+	)
+
+	flags.BoolVar(
+		&ignoreRobots,
+		"ignore-robots",
+		false,
+		"Do not respect robots.txt rules and its crawl delay",
+	)
+
+	flags.StringVar(
+		&sitemap,
+		"sitemap",
+		"",
+		"Seed the crawl with the sitemap, discovered through robots.txt if url is not provided",
+	)
+	flags.Lookup("sitemap").NoOptDefVal = discoverSitemap
+
+	flags.BoolVar(
+		&graph,
+		"graph",
+		false,
+		"Add graph of all the links between the pages, not only the first ones which lead to the page",
+	)
+
+	flags.BoolVar(
+		&checkAssets,
+		"check-assets",
+		false,
+		"Request every asset once and record the missing, empty or of the wrong type ones",
+	)
+
+	flags.BoolVar(
+		&noStylesheets,
+		"no-stylesheets",
+		false,
+		"Do not request the stylesheets for the fonts, images and imports they refer to",
+	)
 }
 
 // Main
@@ -55,6 +55,19 @@ func Error(err error, exitCode int) {
 	os.Exit(exitCode)
 }
 
+// Wait waits for the progress without showing anything, except the error,
+// and returns the exitCode if error occuried
+func Wait(progress chan *spider.Progress) (exitCode int) {
+	for result := range progress {
+		if result.Error != nil {
+			ShowError(result.Error)
+			return 1
+		}
+	}
+
+	return
+}
+
 // Spin shows the spinner with additional message
 // and returns the exitCode if error occuried
 func Spin(progress chan *spider.Progress) (exitCode int) {
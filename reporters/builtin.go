@@ -0,0 +1,180 @@
+package reporters
+
+import (
+	"io"
+
+	"github.com/go-errors/errors"
+
+	"github.com/markelog/map/spider"
+
+	// Reporters
+	"github.com/markelog/map/reporters/csv"
+	"github.com/markelog/map/reporters/dot"
+	"github.com/markelog/map/reporters/html"
+	"github.com/markelog/map/reporters/json"
+	"github.com/markelog/map/reporters/markdown"
+	"github.com/markelog/map/reporters/ndjson"
+	"github.com/markelog/map/reporters/sitemap"
+	"github.com/markelog/map/reporters/template"
+	"github.com/markelog/map/reporters/tree"
+	"github.com/markelog/map/reporters/yaml"
+)
+
+// builtin adapts the reporter package which serializes to a string
+type builtin struct {
+	name    string
+	execute func(data *spider.Result, options Options) (string, error)
+}
+
+// Name of the reporter
+func (reporter *builtin) Name() string {
+	return reporter.name
+}
+
+// Write the result
+func (reporter *builtin) Write(writer io.Writer, data *spider.Result, options Options) error {
+	result, err := reporter.execute(data, options)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(writer, result)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// streaming builtin reporter
+type streaming struct {
+	*builtin
+	stream func(progress chan *spider.Progress, writer io.Writer) chan *spider.Progress
+}
+
+// Stream the pages
+func (reporter *streaming) Stream(
+	progress chan *spider.Progress,
+	writer io.Writer,
+	options Options,
+) chan *spider.Progress {
+	return reporter.stream(progress, writer)
+}
+
+// split builtin reporter which might need more than one file
+type split struct {
+	*builtin
+	files func(data *spider.Result) (map[string]string, error)
+}
+
+// Files of the reporter
+func (reporter *split) Files(data *spider.Result, options Options) (map[string]string, error) {
+	return reporter.files(data)
+}
+
+func init() {
+	Register(&builtin{
+		name: "json",
+		execute: func(data *spider.Result, options Options) (string, error) {
+			return json.Execute(data)
+		},
+	})
+
+	Register(&builtin{
+		name: "yaml",
+		execute: func(data *spider.Result, options Options) (string, error) {
+			return yaml.Execute(data)
+		},
+	})
+
+	Register(&split{
+		builtin: &builtin{
+			name: "sitemap",
+			execute: func(data *spider.Result, options Options) (string, error) {
+				return sitemap.Execute(data)
+			},
+		},
+		files: sitemapFiles,
+	})
+
+	Register(&builtin{
+		name: "dot",
+		execute: func(data *spider.Result, options Options) (string, error) {
+			return dot.Execute(data, options.Cluster)
+		},
+	})
+
+	Register(&builtin{
+		name: "html",
+		execute: func(data *spider.Result, options Options) (string, error) {
+			return html.Execute(data)
+		},
+	})
+
+	Register(&builtin{
+		name: "csv",
+		execute: func(data *spider.Result, options Options) (string, error) {
+			return csv.Execute(data, options.Mode, options.Columns, csv.Comma)
+		},
+	})
+
+	Register(&builtin{
+		name: "tsv",
+		execute: func(data *spider.Result, options Options) (string, error) {
+			return csv.Execute(data, options.Mode, options.Columns, csv.Tab)
+		},
+	})
+
+	Register(&builtin{
+		name: "template",
+		execute: func(data *spider.Result, options Options) (string, error) {
+			return template.Execute(data, options.Template)
+		},
+	})
+
+	Register(&builtin{
+		name: "tree",
+		execute: func(data *spider.Result, options Options) (string, error) {
+			return tree.Execute(data, tree.Options{
+				Assets: options.ShowAssets,
+				Broken: options.ShowBroken,
+				ASCII:  options.ASCII,
+			})
+		},
+	})
+
+	Register(&builtin{
+		name: "markdown",
+		execute: func(data *spider.Result, options Options) (string, error) {
+			return markdown.Execute(data, markdown.Options{
+				Assets: options.ShowAssets,
+				Broken: options.ShowBroken,
+			})
+		},
+	})
+
+	Register(&streaming{
+		builtin: &builtin{
+			name: "ndjson",
+			execute: func(data *spider.Result, options Options) (string, error) {
+				return ndjson.Execute(data)
+			},
+		},
+		stream: ndjson.Stream,
+	})
+}
+
+// sitemapFiles are the parts of the sitemap which is too big for one file
+func sitemapFiles(data *spider.Result) (map[string]string, error) {
+	parts, err := sitemap.Parts(data)
+	if err != nil || len(parts) < 2 {
+		return nil, err
+	}
+
+	files := map[string]string{}
+	for i, part := range parts {
+		files[sitemap.PartName(i)] = part
+	}
+
+	return files, nil
+}
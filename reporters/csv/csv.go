@@ -0,0 +1,237 @@
+// Package csv provides the csv and tsv reporters
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors"
+
+	"github.com/markelog/map/collect"
+	"github.com/markelog/map/spider"
+)
+
+// Kinds of the rows
+const (
+	ModePages = "pages"
+	ModeEdges = "edges"
+)
+
+// Separators of the fields
+const (
+	Comma = ','
+	Tab   = '\t'
+)
+
+// PageColumns are the available columns of the pages mode, in default order
+var PageColumns = []string{
+	"url",
+	"title",
+	"depth",
+	"parent",
+	"status",
+	"assets",
+	"images",
+	"styles",
+	"scripts",
+	"video",
+	"audio",
+	"fonts",
+	"broken",
+}
+
+// EdgeColumns are the available columns of the edges mode, in default order
+var EdgeColumns = []string{
+	"source",
+	"target",
+	"text",
+	"rel",
+	"broken",
+}
+
+// edge is the link between the pages
+type edge struct {
+	source string
+	target string
+	text   string
+	rel    string
+	broken bool
+}
+
+// Execute csv reporter, mode is either "pages" with one row per page
+// or "edges" with one row per link, no columns means all of them
+func Execute(data *spider.Result, mode string, columns []string, comma rune) (string, error) {
+	if len(mode) == 0 {
+		mode = ModePages
+	}
+
+	available := PageColumns
+	if mode == ModeEdges {
+		available = EdgeColumns
+	} else if mode != ModePages {
+		return "", errors.New(`Unknown "` + mode + `" mode, should be either "pages" or "edges"`)
+	}
+
+	if len(columns) == 0 {
+		columns = available
+	}
+
+	for _, column := range columns {
+		if contains(available, column) == false {
+			return "", errors.New(
+				`Unknown "` + column + `" column, should be one of ` + strings.Join(available, ", "),
+			)
+		}
+	}
+
+	var (
+		buffer = &bytes.Buffer{}
+		writer = csv.NewWriter(buffer)
+		pages  = spider.Flatten(data)
+	)
+
+	writer.Comma = comma
+	writer.Write(columns)
+
+	if mode == ModePages {
+		for _, item := range pages {
+			writer.Write(pageRow(item, columns))
+		}
+	} else {
+		for _, item := range edges(data, pages) {
+			writer.Write(edgeRow(item, columns))
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", errors.New(err)
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// edges between the pages, all of them if there is a graph,
+// otherwise only the ones which lead to the pages, broken ones included
+func edges(data *spider.Result, pages []*spider.Page) (result []*edge) {
+	if data.Graph != nil {
+		broken := map[[2]string]bool{}
+		for _, item := range pages {
+			for _, link := range item.Broken {
+				broken[[2]string{item.URL, link.URL}] = true
+			}
+		}
+
+		// Graph has the broken links as well, they are only marked
+		for _, link := range data.Graph.Edges {
+			result = append(result, &edge{
+				source: link.Source,
+				target: link.Target,
+				text:   link.Text,
+				rel:    link.Rel,
+				broken: broken[[2]string{link.Source, link.Target}],
+			})
+		}
+
+		return
+	}
+
+	for _, item := range pages {
+		if len(item.Parent) == 0 {
+			continue
+		}
+
+		result = append(result, &edge{
+			source: item.Parent,
+			target: item.URL,
+		})
+	}
+
+	// Tree does not have the broken links
+	for _, item := range pages {
+		for _, link := range item.Broken {
+			result = append(result, &edge{
+				source: item.URL,
+				target: link.URL,
+				text:   link.Text,
+				broken: true,
+			})
+		}
+	}
+
+	return
+}
+
+// pageRow gets the fields of the page
+func pageRow(item *spider.Page, columns []string) []string {
+	row := make([]string, len(columns))
+
+	for i, column := range columns {
+		switch column {
+		case "url":
+			row[i] = item.URL
+		case "title":
+			row[i] = item.Name
+		case "depth":
+			row[i] = strconv.Itoa(item.Depth)
+		case "parent":
+			row[i] = item.Parent
+		case "status":
+			if item.Response != nil {
+				row[i] = strconv.Itoa(item.Response.StatusCode)
+			}
+		case "assets":
+			row[i] = strconv.Itoa(count(item.Assets))
+		case "broken":
+			row[i] = strconv.Itoa(len(item.Broken))
+		default:
+			row[i] = strconv.Itoa(len(item.Assets[column]))
+		}
+	}
+
+	return row
+}
+
+// edgeRow gets the fields of the edge
+func edgeRow(item *edge, columns []string) []string {
+	row := make([]string, len(columns))
+
+	for i, column := range columns {
+		switch column {
+		case "source":
+			row[i] = item.source
+		case "target":
+			row[i] = item.target
+		case "text":
+			row[i] = item.text
+		case "rel":
+			row[i] = item.rel
+		case "broken":
+			row[i] = strconv.FormatBool(item.broken)
+		}
+	}
+
+	return row
+}
+
+// count all the assets
+func count(assets map[string][]*collect.Asset) (result int) {
+	for _, list := range assets {
+		result += len(list)
+	}
+
+	return
+}
+
+// contains finds out if the list has such value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
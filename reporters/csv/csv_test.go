@@ -0,0 +1,103 @@
+package csv_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/markelog/map/collect"
+	. "github.com/markelog/map/reporters/csv"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("reporters", func() {
+	Describe("CSV", func() {
+		var data *spider.Result
+
+		BeforeEach(func() {
+			data = &spider.Result{
+				URL:  "http://example.com",
+				Name: "Home, sweet home",
+				Assets: map[string][]*collect.Asset{
+					"images": {{URL: "http://example.com/a.png"}, {URL: "http://example.com/b.png"}},
+					"styles": {{URL: "http://example.com/main.css"}},
+				},
+				Broken: []*spider.Broken{
+					{URL: "http://example.com/nope", Text: "Nope"},
+				},
+				Response: &spider.Response{StatusCode: 200},
+				Children: []*spider.Result{
+					{
+						URL:  "http://example.com/a",
+						Name: "A",
+						Children: []*spider.Result{
+							{URL: "http://example.com/b", Name: "B"},
+						},
+					},
+				},
+			}
+		})
+
+		It("Executes pages mode", func() {
+			expected := `url,title,depth,parent,status,assets,images,styles,scripts,video,audio,fonts,broken
+http://example.com,"Home, sweet home",0,,200,3,2,1,0,0,0,0,1
+http://example.com/a,A,1,http://example.com,,0,0,0,0,0,0,0,0
+http://example.com/b,B,2,http://example.com/a,,0,0,0,0,0,0,0,0`
+
+			result, err := Execute(data, ModePages, nil, Comma)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		})
+
+		It("Selects the columns", func() {
+			expected := "title\turl\nHome, sweet home\thttp://example.com\nA\thttp://example.com/a\nB\thttp://example.com/b"
+
+			result, err := Execute(data, ModePages, []string{"title", "url"}, Tab)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		})
+
+		It("Executes edges mode", func() {
+			expected := `source,target,text,rel,broken
+http://example.com,http://example.com/a,,,false
+http://example.com/a,http://example.com/b,,,false
+http://example.com,http://example.com/nope,Nope,,true`
+
+			result, err := Execute(data, ModeEdges, nil, Comma)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		})
+
+		It("Uses the graph for the edges", func() {
+			data.Graph = &spider.Graph{
+				Edges: []*spider.Edge{
+					{Source: "http://example.com", Target: "http://example.com/b", Text: "B", Rel: "next"},
+					{Source: "http://example.com", Target: "http://example.com/nope", Text: "Nope"},
+				},
+			}
+
+			// Broken link is in the graph, so it's there once
+			expected := `source,target,text,rel,broken
+http://example.com,http://example.com/b,B,next,false
+http://example.com,http://example.com/nope,Nope,,true`
+
+			result, _ := Execute(data, ModeEdges, []string{"source", "target", "text", "rel", "broken"}, Comma)
+
+			Expect(result).To(Equal(expected))
+		})
+
+		It("Returns error for unknown mode", func() {
+			_, err := Execute(data, "nope", nil, Comma)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Returns error for unknown column", func() {
+			_, err := Execute(data, ModeEdges, []string{"title"}, Comma)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
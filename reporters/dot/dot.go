@@ -0,0 +1,180 @@
+// Package dot provides the Graphviz DOT reporter
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors"
+
+	"github.com/markelog/map/spider"
+)
+
+// Ways to cluster the pages
+const (
+	ClusterNone   = ""
+	ClusterPath   = "path"
+	ClusterDomain = "domain"
+)
+
+// page is the node of the digraph
+type page struct {
+	url    string
+	label  string
+	broken bool
+}
+
+// Execute dot reporter, pages might be clustered by
+// the first segment of their path or by their domain
+func Execute(data *spider.Result, cluster string) (string, error) {
+	if cluster != ClusterNone && cluster != ClusterPath && cluster != ClusterDomain {
+		return "", errors.New(`Unknown "` + cluster + `" cluster, should be either "path" or "domain"`)
+	}
+
+	var (
+		pages  = []*page{}
+		known  = map[string]*page{}
+		edges  = [][2]string{}
+		buffer = &bytes.Buffer{}
+	)
+
+	add := func(node *page) {
+		if _, ok := known[node.url]; ok {
+			return
+		}
+
+		known[node.url] = node
+		pages = append(pages, node)
+	}
+
+	nodes := spider.Flatten(data)
+
+	for _, node := range nodes {
+		add(&page{url: node.URL, label: label(node.Result)})
+
+		if len(node.Parent) > 0 {
+			edges = append(edges, [2]string{node.Parent, node.URL})
+		}
+	}
+
+	// Graph knows all the links, not only the first ones
+	if data.Graph != nil {
+		edges = [][2]string{}
+
+		for _, edge := range data.Graph.Edges {
+			if _, ok := known[edge.Target]; ok {
+				edges = append(edges, [2]string{edge.Source, edge.Target})
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		for _, link := range node.Broken {
+			add(&page{url: link.URL, label: link.URL, broken: true})
+			edges = append(edges, [2]string{node.URL, link.URL})
+		}
+	}
+
+	fmt.Fprintln(buffer, "digraph map {")
+	fmt.Fprintln(buffer, "  node [shape=box];")
+
+	if cluster == ClusterNone {
+		for _, node := range pages {
+			fmt.Fprintf(buffer, "  %s;\n", declare(node))
+		}
+	} else {
+		groups, names := group(pages, cluster)
+
+		for i, name := range names {
+			fmt.Fprintf(buffer, "  subgraph cluster_%d {\n", i)
+			fmt.Fprintf(buffer, "    label=%s;\n", quote(name))
+
+			for _, node := range groups[name] {
+				fmt.Fprintf(buffer, "    %s;\n", declare(node))
+			}
+
+			fmt.Fprintln(buffer, "  }")
+		}
+	}
+
+	for _, edge := range edges {
+		attributes := ""
+		if known[edge[1]].broken {
+			attributes = " [color=red]"
+		}
+
+		fmt.Fprintf(buffer, "  %s -> %s%s;\n", quote(edge[0]), quote(edge[1]), attributes)
+	}
+
+	fmt.Fprint(buffer, "}")
+
+	return buffer.String(), nil
+}
+
+// label of the page node
+func label(node *spider.Result) string {
+	if len(strings.TrimSpace(node.Name)) == 0 {
+		return node.URL
+	}
+
+	return node.Name
+}
+
+// declare the node with its attributes
+func declare(node *page) string {
+	attributes := "label=" + quote(node.label)
+
+	if node.broken {
+		attributes += ", color=red, fontcolor=red"
+	}
+
+	return quote(node.url) + " [" + attributes + "]"
+}
+
+// group pages by the cluster name, names are sorted
+func group(pages []*page, cluster string) (map[string][]*page, []string) {
+	groups := map[string][]*page{}
+	names := []string{}
+
+	for _, node := range pages {
+		name := clusterName(node.url, cluster)
+
+		if _, ok := groups[name]; ok == false {
+			names = append(names, name)
+		}
+
+		groups[name] = append(groups[name], node)
+	}
+
+	sort.Strings(names)
+
+	return groups, names
+}
+
+// clusterName finds out to which cluster the url belongs
+func clusterName(link, cluster string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	if cluster == ClusterDomain {
+		return parsed.Host
+	}
+
+	segments := strings.SplitN(strings.TrimPrefix(parsed.Path, "/"), "/", 2)
+
+	return "/" + segments[0]
+}
+
+// quote makes DOT string
+func quote(text string) string {
+	text = strings.Replace(text, `\`, `\\`, -1)
+	text = strings.Replace(text, `"`, `\"`, -1)
+	text = strings.Replace(text, "\n", `\n`, -1)
+
+	return `"` + text + `"`
+}
@@ -0,0 +1,104 @@
+package dot_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/reporters/dot"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("reporters", func() {
+	Describe("Dot", func() {
+		var data *spider.Result
+
+		BeforeEach(func() {
+			data = &spider.Result{
+				URL:  "http://example.com",
+				Name: `The "site"`,
+				Broken: []*spider.Broken{
+					{URL: "http://example.com/nope"},
+				},
+				Children: []*spider.Result{
+					{
+						URL:  "http://example.com/docs/a",
+						Name: "A",
+						Children: []*spider.Result{
+							{URL: "http://example.net/b"},
+						},
+					},
+				},
+			}
+		})
+
+		It("Executes dot reporter", func() {
+			expected := `digraph map {
+  node [shape=box];
+  "http://example.com" [label="The \"site\""];
+  "http://example.com/docs/a" [label="A"];
+  "http://example.net/b" [label="http://example.net/b"];
+  "http://example.com/nope" [label="http://example.com/nope", color=red, fontcolor=red];
+  "http://example.com" -> "http://example.com/docs/a";
+  "http://example.com/docs/a" -> "http://example.net/b";
+  "http://example.com" -> "http://example.com/nope" [color=red];
+}`
+			result, err := Execute(data, ClusterNone)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		})
+
+		It("Clusters pages by path", func() {
+			result, _ := Execute(data, ClusterPath)
+
+			Expect(result).To(ContainSubstring(`  subgraph cluster_0 {
+    label="/";
+    "http://example.com" [label="The \"site\""];
+  }
+  subgraph cluster_1 {
+    label="/b";
+    "http://example.net/b" [label="http://example.net/b"];
+  }
+  subgraph cluster_2 {
+    label="/docs";
+    "http://example.com/docs/a" [label="A"];
+  }`))
+		})
+
+		It("Clusters pages by domain", func() {
+			result, _ := Execute(data, ClusterDomain)
+
+			Expect(result).To(ContainSubstring(`  subgraph cluster_0 {
+    label="example.com";
+    "http://example.com" [label="The \"site\""];
+    "http://example.com/docs/a" [label="A"];
+    "http://example.com/nope" [label="http://example.com/nope", color=red, fontcolor=red];
+  }
+  subgraph cluster_1 {
+    label="example.net";
+    "http://example.net/b" [label="http://example.net/b"];
+  }`))
+		})
+
+		It("Uses all the links of the graph", func() {
+			data.Graph = &spider.Graph{
+				Edges: []*spider.Edge{
+					{Source: "http://example.net/b", Target: "http://example.com"},
+					{Source: "http://example.net/b", Target: "http://example.org"},
+				},
+			}
+
+			result, _ := Execute(data, ClusterNone)
+
+			Expect(result).To(ContainSubstring(`"http://example.net/b" -> "http://example.com";`))
+			Expect(result).ToNot(ContainSubstring(`"http://example.com" -> "http://example.com/docs/a";`))
+			Expect(result).ToNot(ContainSubstring(`example.org`))
+		})
+
+		It("Returns error for unknown cluster", func() {
+			_, err := Execute(data, "nope")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
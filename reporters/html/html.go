@@ -0,0 +1,139 @@
+// Package html provides the standalone HTML reporter
+package html
+
+import (
+	"bytes"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors"
+
+	"github.com/markelog/map/collect"
+	"github.com/markelog/map/spider"
+)
+
+// page is the row of the pages table
+type page struct {
+	URL    string
+	Name   string
+	Parent string
+	Depth  int
+	Status int
+	Assets []*category
+	Broken []*spider.Broken
+
+	AssetsCount int
+}
+
+// category of the page assets
+type category struct {
+	Name   string
+	Assets []*collect.Asset
+}
+
+// broken is the row of the broken links table
+type broken struct {
+	Page string
+	Link *spider.Broken
+}
+
+// brokenAsset is the row of the broken assets table
+type brokenAsset struct {
+	Page  string
+	Asset *spider.BrokenAsset
+}
+
+// view is the data of the report
+type view struct {
+	Title  string
+	Root   *spider.Result
+	Pages  []*page
+	Broken []*broken
+	Assets []*brokenAsset
+}
+
+// report is the compiled template of the report
+var report = template.Must(template.New("report").Parse(layout))
+
+// Execute html reporter
+func Execute(data *spider.Result) (string, error) {
+	var (
+		buffer = &bytes.Buffer{}
+		result = &view{
+			Title: title(data),
+			Root:  data,
+		}
+	)
+
+	for _, node := range spider.Flatten(data) {
+		row := &page{
+			URL:    node.URL,
+			Name:   node.Name,
+			Parent: node.Parent,
+			Depth:  node.Depth,
+			Broken: node.Broken,
+			Assets: categories(node.Assets),
+		}
+
+		if node.Response != nil {
+			row.Status = node.Response.StatusCode
+		}
+
+		for _, category := range row.Assets {
+			row.AssetsCount += len(category.Assets)
+		}
+
+		result.Pages = append(result.Pages, row)
+
+		for _, link := range node.Broken {
+			result.Broken = append(result.Broken, &broken{
+				Page: node.URL,
+				Link: link,
+			})
+		}
+
+		for _, asset := range node.BrokenAssets {
+			result.Assets = append(result.Assets, &brokenAsset{
+				Page:  node.URL,
+				Asset: asset,
+			})
+		}
+	}
+
+	err := report.Execute(buffer, result)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	return buffer.String(), nil
+}
+
+// title of the report
+func title(data *spider.Result) string {
+	if len(strings.TrimSpace(data.Name)) == 0 {
+		return data.URL
+	}
+
+	return data.Name
+}
+
+// categories of the assets sorted by their name, without empty ones
+func categories(assets map[string][]*collect.Asset) (result []*category) {
+	for name, list := range assets {
+		if len(list) == 0 {
+			continue
+		}
+
+		result = append(result, &category{
+			Name:   name,
+			Assets: list,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return
+}
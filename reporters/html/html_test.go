@@ -0,0 +1,98 @@
+package html_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/markelog/map/collect"
+	. "github.com/markelog/map/reporters/html"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("reporters", func() {
+	Describe("HTML", func() {
+		var data *spider.Result
+
+		BeforeEach(func() {
+			data = &spider.Result{
+				URL:  "http://example.com",
+				Name: "<Site>",
+				Assets: map[string][]*collect.Asset{
+					"styles":  {{URL: "http://example.com/main.css"}},
+					"scripts": {},
+				},
+				Broken: []*spider.Broken{
+					{
+						URL:        "http://example.com/nope",
+						StatusCode: 404,
+						Class:      spider.ClassHTTP,
+						Message:    "Not Found",
+						Text:       "Nope",
+					},
+				},
+				Children: []*spider.Result{
+					{
+						URL:  "http://example.com/a",
+						Name: "A",
+						Response: &spider.Response{
+							StatusCode: 200,
+						},
+					},
+				},
+			}
+		})
+
+		It("Is standalone", func() {
+			result, err := Execute(data)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HavePrefix("<!DOCTYPE html>"))
+			Expect(result).ToNot(ContainSubstring("<script src"))
+			Expect(result).ToNot(ContainSubstring("<link"))
+		})
+
+		It("Escapes the titles", func() {
+			result, _ := Execute(data)
+
+			Expect(result).To(ContainSubstring("<title>Map of &lt;Site&gt;</title>"))
+			Expect(result).ToNot(ContainSubstring("<Site>"))
+		})
+
+		It("Includes the tree", func() {
+			result, _ := Execute(data)
+
+			Expect(result).To(ContainSubstring(`<summary><a href="http://example.com">&lt;Site&gt;</a> <span class="broken">(1 broken)</span></summary>`))
+			Expect(result).To(ContainSubstring(`<li class="leaf"><a href="http://example.com/a">A</a></li>`))
+		})
+
+		It("Includes the pages with their assets", func() {
+			result, _ := Execute(data)
+
+			Expect(result).To(ContainSubstring("2 pages, 1 broken links"))
+			Expect(result).To(ContainSubstring(`<li><a href="http://example.com/main.css">http://example.com/main.css</a></li>`))
+			Expect(result).To(ContainSubstring("<td>200</td>"))
+			Expect(result).ToNot(ContainSubstring("<div>scripts</div>"))
+		})
+
+		It("Describes the types of the assets", func() {
+			data.Assets["scripts"] = []*collect.Asset{
+				{URL: "http://example.net/app.js", Type: collect.AssetExternal},
+				{URL: "data:text/javascript,alert(1)", Type: collect.AssetData},
+				{Type: collect.AssetInline, Selector: "body > script:nth-child(1)"},
+			}
+
+			result, _ := Execute(data)
+
+			Expect(result).To(ContainSubstring(`<li><a href="http://example.net/app.js">http://example.net/app.js</a> <span class="muted">external</span></li>`))
+			Expect(result).To(ContainSubstring(`<li>data url</li>`))
+			Expect(result).To(ContainSubstring(`<li>inline <span class="muted">body &gt; script:nth-child(1)</span></li>`))
+		})
+
+		It("Includes the broken links", func() {
+			result, _ := Execute(data)
+
+			Expect(result).To(ContainSubstring(`<td>404 http<div class="muted">Not Found</div></td>`))
+			Expect(result).To(ContainSubstring(`<div class="muted">"Nope"</div>`))
+		})
+	})
+})
@@ -0,0 +1,136 @@
+package html
+
+// layout of the report, everything is embedded so the file is standalone
+const layout = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Map of {{.Title}}</title>
+<style>
+  body { font: 14px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+  h1 { font-size: 1.6em; }
+  h2 { font-size: 1.3em; margin-top: 2em; border-bottom: 1px solid #eaecef; }
+  a { color: #0366d6; text-decoration: none; }
+  a:hover { text-decoration: underline; }
+  ul.tree, ul.tree ul { list-style: none; padding-left: 1.2em; }
+  ul.tree summary { cursor: pointer; }
+  ul.tree li.leaf { padding-left: 1em; }
+  .muted { color: #6a737d; }
+  .broken { color: #cb2431; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { text-align: left; padding: .3em .6em; border-bottom: 1px solid #eaecef; vertical-align: top; }
+  th { background: #f6f8fa; }
+  input[type=search] { width: 100%; padding: .5em; margin-bottom: 1em; box-sizing: border-box; }
+</style>
+</head>
+<body>
+<h1>Map of <a href="{{.Root.URL}}">{{.Title}}</a></h1>
+<p class="muted">{{len .Pages}} pages, {{len .Broken}} broken links</p>
+
+<h2>Tree</h2>
+<ul class="tree">
+{{template "node" .Root}}
+</ul>
+
+<h2>Pages</h2>
+<input type="search" id="filter" placeholder="Filter pages">
+<table id="pages">
+  <thead>
+    <tr><th>Page</th><th>Depth</th><th>Status</th><th>Assets</th><th>Broken</th></tr>
+  </thead>
+  <tbody>
+  {{range .Pages}}
+    <tr>
+      <td>
+        <a href="{{.URL}}">{{if .Name}}{{.Name}}{{else}}{{.URL}}{{end}}</a>
+        <div class="muted">{{.URL}}</div>
+      </td>
+      <td>{{.Depth}}</td>
+      <td>{{if .Status}}{{.Status}}{{end}}</td>
+      <td>
+        {{if .Assets}}
+        <details>
+          <summary>{{.AssetsCount}}</summary>
+          {{range .Assets}}
+          <div>{{.Name}}</div>
+          <ul>{{range .Assets}}<li>{{template "asset" .}}</li>{{end}}</ul>
+          {{end}}
+        </details>
+        {{else}}0{{end}}
+      </td>
+      <td{{if .Broken}} class="broken"{{end}}>{{len .Broken}}</td>
+    </tr>
+  {{end}}
+  </tbody>
+</table>
+
+<h2>Broken links</h2>
+{{if .Broken}}
+<table>
+  <thead>
+    <tr><th>Link</th><th>Reason</th><th>Found on</th></tr>
+  </thead>
+  <tbody>
+  {{range .Broken}}
+    <tr>
+      <td class="broken">{{.Link.URL}}{{if .Link.Text}}<div class="muted">"{{.Link.Text}}"</div>{{end}}</td>
+      <td>{{if .Link.StatusCode}}{{.Link.StatusCode}} {{end}}{{.Link.Class}}<div class="muted">{{.Link.Message}}</div></td>
+      <td><a href="{{.Page}}">{{.Page}}</a>{{if .Link.Selector}}<div class="muted">{{.Link.Selector}}</div>{{end}}</td>
+    </tr>
+  {{end}}
+  </tbody>
+</table>
+{{else}}
+<p class="muted">None</p>
+{{end}}
+
+{{if .Assets}}
+<h2>Broken assets</h2>
+<table>
+  <thead>
+    <tr><th>Asset</th><th>Reason</th><th>Found on</th></tr>
+  </thead>
+  <tbody>
+  {{range .Assets}}
+    <tr>
+      <td class="broken">{{.Asset.URL}}<div class="muted">{{.Asset.Kind}}</div></td>
+      <td>{{if .Asset.StatusCode}}{{.Asset.StatusCode}} {{end}}{{.Asset.Class}}<div class="muted">{{.Asset.Message}}</div></td>
+      <td><a href="{{.Page}}">{{.Page}}</a></td>
+    </tr>
+  {{end}}
+  </tbody>
+</table>
+{{end}}
+
+<script>
+  document.getElementById("filter").addEventListener("input", function (event) {
+    var query = event.target.value.toLowerCase();
+    var rows = document.querySelectorAll("#pages tbody tr");
+
+    for (var i = 0; i < rows.length; i++) {
+      var text = rows[i].textContent.toLowerCase();
+      rows[i].style.display = text.indexOf(query) === -1 ? "none" : "";
+    }
+  });
+</script>
+</body>
+</html>
+{{define "asset"}}
+{{- if eq .Type "inline"}}inline <span class="muted">{{.Selector}}</span>
+{{- else if eq .Type "data"}}data url
+{{- else}}<a href="{{.URL}}">{{.URL}}</a>{{if .Type}} <span class="muted">{{.Type}}</span>{{end}}
+{{- end}}
+{{- end}}
+{{define "node"}}
+{{if .Children}}
+<li>
+  <details open>
+    <summary><a href="{{.URL}}">{{if .Name}}{{.Name}}{{else}}{{.URL}}{{end}}</a>{{if .Broken}} <span class="broken">({{len .Broken}} broken)</span>{{end}}</summary>
+    <ul>{{range .Children}}{{template "node" .}}{{end}}</ul>
+  </details>
+</li>
+{{else}}
+<li class="leaf"><a href="{{.URL}}">{{if .Name}}{{.Name}}{{else}}{{.URL}}{{end}}</a>{{if .Broken}} <span class="broken">({{len .Broken}} broken)</span>{{end}}</li>
+{{end}}
+{{end}}
+`
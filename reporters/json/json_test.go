@@ -16,5 +16,24 @@ var _ = Describe("reporters", func() {
 
 			Expect(result).To(Equal(expected))
 		})
+
+		It("Includes the response", func() {
+			expected := `{"assets":null,"url":"","name":"test","links":null,"broken":null,"children":null,` +
+				`"response":{"statusCode":200,"url":"http://example.com","contentType":"text/html",` +
+				`"contentLength":10,"time":5}}`
+
+			result, _ := Execute(&spider.Result{
+				Name: "test",
+				Response: &spider.Response{
+					StatusCode:    200,
+					URL:           "http://example.com",
+					ContentType:   "text/html",
+					ContentLength: 10,
+					Time:          5,
+				},
+			})
+
+			Expect(result).To(Equal(expected))
+		})
 	})
 })
@@ -0,0 +1,76 @@
+// Package markdown provides the reporter which outputs
+// the pages as nested lists of links
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/markelog/map/spider"
+)
+
+// Options of the lists
+type Options struct {
+	// Assets shows the asset counts of the pages
+	Assets bool
+
+	// Broken shows the broken links of the pages
+	Broken bool
+}
+
+// escaping of the link text
+var escape = strings.NewReplacer(
+	`\`, `\\`,
+	"[", `\[`,
+	"]", `\]`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"<", `\<`,
+	">", `\>`,
+)
+
+// Execute markdown reporter
+func Execute(data *spider.Result, options Options) (string, error) {
+	buffer := &bytes.Buffer{}
+
+	var walk func(node *spider.Result, depth int)
+	walk = func(node *spider.Result, depth int) {
+		indent := strings.Repeat("  ", depth)
+
+		buffer.WriteString(indent + "- " + link(node))
+
+		if options.Assets {
+			if summary := node.AssetsSummary(); len(summary) > 0 {
+				buffer.WriteString(" (" + summary + ")")
+			}
+		}
+
+		buffer.WriteString("\n")
+
+		for _, child := range node.Children {
+			walk(child, depth+1)
+		}
+
+		if options.Broken {
+			for _, broken := range node.Broken {
+				buffer.WriteString(
+					indent + "  - ~~<" + broken.URL + ">~~ " + broken.Reason() + "\n",
+				)
+			}
+		}
+	}
+	walk(data, 0)
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// link to the page
+func link(data *spider.Result) string {
+	name := strings.TrimSpace(data.Name)
+	if len(name) == 0 {
+		name = data.URL
+	}
+
+	return "[" + escape.Replace(name) + "](<" + data.URL + ">)"
+}
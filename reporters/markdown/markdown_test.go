@@ -0,0 +1,59 @@
+package markdown_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/markelog/map/collect"
+	. "github.com/markelog/map/reporters/markdown"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("reporters", func() {
+	Describe("Markdown", func() {
+		var data *spider.Result
+
+		BeforeEach(func() {
+			data = &spider.Result{
+				URL:  "http://example.com",
+				Name: "[Home]",
+				Assets: map[string][]*collect.Asset{
+					"scripts": {{URL: "http://example.com/main.js"}},
+				},
+				Broken: []*spider.Broken{
+					{URL: "http://example.com/nope", StatusCode: 404},
+				},
+				Children: []*spider.Result{
+					{
+						URL: "http://example.com/a_(b)",
+						Children: []*spider.Result{
+							{URL: "http://example.com/c", Name: "C"},
+						},
+					},
+				},
+			}
+		})
+
+		It("Executes markdown reporter", func() {
+			expected := `- [\[Home\]](<http://example.com>)
+  - [http://example.com/a\_(b)](<http://example.com/a_(b)>)
+    - [C](<http://example.com/c>)`
+
+			result, err := Execute(data, Options{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		})
+
+		It("Shows the assets and the broken links", func() {
+			expected := `- [\[Home\]](<http://example.com>) (1 scripts)
+  - [http://example.com/a\_(b)](<http://example.com/a_(b)>)
+    - [C](<http://example.com/c>)
+  - ~~<http://example.com/nope>~~ 404`
+
+			result, _ := Execute(data, Options{Assets: true, Broken: true})
+
+			Expect(result).To(Equal(expected))
+		})
+	})
+})
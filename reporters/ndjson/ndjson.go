@@ -0,0 +1,110 @@
+// Package ndjson provides the newline delimited json reporter,
+// which is able to write the pages while they are crawled
+package ndjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/go-errors/errors"
+
+	"github.com/markelog/map/collect"
+	"github.com/markelog/map/spider"
+)
+
+// Page is one line of the report, it has only the data which
+// is known at the moment the page is crawled
+type Page struct {
+	URL      string                      `json:"url"`
+	Name     string                      `json:"name"`
+	Parent   string                      `json:"parent"`
+	Depth    int                         `json:"depth"`
+	Links    []string                    `json:"links"`
+	Assets   map[string][]*collect.Asset `json:"assets"`
+	Response *spider.Response            `json:"response,omitempty"`
+}
+
+// NewPage makes the line of the report out of the result
+func NewPage(data *spider.Result) *Page {
+	page := &Page{
+		URL:      data.URL,
+		Name:     data.Name,
+		Depth:    data.Depth(),
+		Links:    data.Links,
+		Assets:   data.Assets,
+		Response: data.Response,
+	}
+
+	if parent := data.Parent(); parent != nil {
+		page.Parent = parent.URL
+	}
+
+	return page
+}
+
+// Execute ndjson reporter for the already crawled pages
+func Execute(data *spider.Result) (string, error) {
+	buffer := &bytes.Buffer{}
+
+	// Loaded results don't know their parents, so take them from the walk
+	for _, node := range spider.Flatten(data) {
+		page := NewPage(node.Result)
+		page.Parent = node.Parent
+		page.Depth = node.Depth
+
+		err := write(buffer, page)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return string(bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))), nil
+}
+
+// Write one page as a line
+func Write(writer io.Writer, data *spider.Result) error {
+	return write(writer, NewPage(data))
+}
+
+// write the line
+func write(writer io.Writer, page *Page) error {
+	line, err := json.Marshal(page)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	_, err = writer.Write(append(line, '\n'))
+	if err != nil {
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// Stream writes the pages while they come through the progress and
+// passes the progress further, so it still could be shown.
+// Error of the writing is reported through the progress as well
+func Stream(progress chan *spider.Progress, writer io.Writer) chan *spider.Progress {
+	result := make(chan *spider.Progress)
+
+	go func() {
+		defer close(result)
+
+		failed := false
+		for item := range progress {
+			if item.Data != nil && failed == false {
+				err := Write(writer, item.Data)
+
+				if err != nil {
+					failed = true
+					item = &spider.Progress{Error: err}
+				}
+			}
+
+			result <- item
+		}
+	}()
+
+	return result
+}
@@ -0,0 +1,98 @@
+package ndjson_test
+
+import (
+	"bytes"
+
+	"github.com/go-errors/errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/reporters/ndjson"
+	"github.com/markelog/map/spider"
+)
+
+// failing writer
+type failing struct{}
+
+func (failing) Write(data []byte) (int, error) {
+	return 0, errors.New("nope")
+}
+
+var _ = Describe("reporters", func() {
+	Describe("NDJSON", func() {
+		var data *spider.Result
+
+		BeforeEach(func() {
+			data = &spider.Result{
+				URL:  "http://example.com",
+				Name: "Home",
+				Children: []*spider.Result{
+					{
+						URL:   "http://example.com/a",
+						Name:  "A",
+						Links: []string{"http://example.com/b"},
+						Children: []*spider.Result{
+							{URL: "http://example.com/b", Name: "B"},
+						},
+					},
+				},
+			}
+		})
+
+		It("Executes ndjson reporter", func() {
+			expected := `{"url":"http://example.com","name":"Home","parent":"","depth":0,"links":null,"assets":null}
+{"url":"http://example.com/a","name":"A","parent":"http://example.com","depth":1,"links":["http://example.com/b"],"assets":null}
+{"url":"http://example.com/b","name":"B","parent":"http://example.com/a","depth":2,"links":null,"assets":null}`
+
+			result, err := Execute(data)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		})
+
+		Describe("Stream", func() {
+			It("Writes the pages and passes the progress further", func() {
+				var (
+					buffer   = &bytes.Buffer{}
+					progress = make(chan *spider.Progress)
+					result   = Stream(progress, buffer)
+				)
+
+				go func() {
+					progress <- &spider.Progress{Data: data}
+					progress <- &spider.Progress{Data: data.Children[0]}
+					close(progress)
+				}()
+
+				count := 0
+				for item := range result {
+					Expect(item.Error).To(BeNil())
+					count++
+				}
+
+				Expect(count).To(Equal(2))
+				Expect(buffer.String()).To(Equal(
+					`{"url":"http://example.com","name":"Home","parent":"","depth":0,"links":null,"assets":null}
+{"url":"http://example.com/a","name":"A","parent":"","depth":0,"links":["http://example.com/b"],"assets":null}
+`,
+				))
+			})
+
+			It("Reports the writing error", func() {
+				var (
+					progress = make(chan *spider.Progress, 1)
+					result   = Stream(progress, failing{})
+				)
+
+				progress <- &spider.Progress{Data: data}
+				close(progress)
+
+				item := <-result
+
+				Expect(item.Error).To(HaveOccurred())
+				Expect(item.Error.Error()).To(Equal("nope"))
+			})
+		})
+	})
+})
@@ -2,41 +2,169 @@
 package reporters
 
 import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+
 	"github.com/go-errors/errors"
 
 	"github.com/markelog/map/spider"
+)
+
+// Reporter serializes the crawl result
+type Reporter interface {
+	// Name of the reporter, like "json"
+	Name() string
+
+	// Write the result with provided options
+	Write(writer io.Writer, data *spider.Result, options Options) error
+}
+
+// Streamer is the reporter which is able to write
+// the pages while they are crawled
+type Streamer interface {
+	Reporter
+
+	// Stream writes the pages while they come through the progress
+	// and passes the progress further, so it still could be shown
+	Stream(progress chan *spider.Progress, writer io.Writer, options Options) chan *spider.Progress
+}
+
+// Filer is the reporter which might need more than one file
+type Filer interface {
+	Reporter
+
+	// Files returns additional files keyed by their names
+	Files(data *spider.Result, options Options) (map[string]string, error)
+}
+
+// Options of the reporters
+type Options struct {
+	// Cluster pages of the dot reporter by "path" or "domain"
+	Cluster string
 
-	// Reporters
-	"github.com/markelog/map/reporters/json"
-	"github.com/markelog/map/reporters/yaml"
+	// Mode of the csv and tsv reporters, either "pages" or "edges"
+	Mode string
+
+	// Columns of the csv and tsv reporters, all of them if empty
+	Columns []string
+
+	// Template file of the template reporter
+	Template string
+
+	// ShowAssets adds asset counts to the tree and markdown reporters
+	ShowAssets bool
+
+	// ShowBroken adds broken links to the tree and markdown reporters
+	ShowBroken bool
+
+	// ASCII draws the tree without unicode characters
+	ASCII bool
+}
+
+var (
+	registry = map[string]Reporter{}
+	mutex    = &sync.RWMutex{}
 )
 
-// Available list of reporters
-var Available = map[string]bool{
-	"json": true,
-	"yaml": true,
+// Register makes the reporter available by its name,
+// it panics if reporter with the same name is already registered
+func Register(reporter Reporter) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if reporter == nil {
+		panic("reporters: Register reporter is nil")
+	}
+
+	name := reporter.Name()
+	if _, ok := registry[name]; ok {
+		panic("reporters: Register called twice for " + name + " reporter")
+	}
+
+	registry[name] = reporter
+}
+
+// Get the registered reporter
+func Get(name string) (reporter Reporter, ok bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	reporter, ok = registry[name]
+
+	return
+}
+
+// Names of the registered reporters, sorted
+func Names() (result []string) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	for name := range registry {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+
+	return
 }
 
 // Exist finds out if such reporter exist
 func Exist(name string) (ok bool) {
-	_, ok = Available[name]
+	_, ok = Get(name)
 
 	return
 }
 
 // Execute gets the requested reporter and feeds data to it
-func Execute(name string, data *spider.Result) (string, error) {
+func Execute(name string, data *spider.Result, options Options) (string, error) {
 	if data == nil {
 		return "", nil
 	}
 
-	if name == "json" {
-		return json.Execute(data)
+	reporter, ok := Get(name)
+	if ok == false {
+		return "", errors.New(name + " reporter doesn't exist")
 	}
 
-	if name == "yaml" {
-		return yaml.Execute(data)
+	buffer := &bytes.Buffer{}
+	err := reporter.Write(buffer, data, options)
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// Streams finds out if the reporter is able to write
+// the pages while they are crawled
+func Streams(name string) bool {
+	reporter, _ := Get(name)
+	_, ok := reporter.(Streamer)
+
+	return ok
+}
+
+// Stream writes the pages to the writer while they come through the progress,
+// the progress is passed further untouched if the reporter can't stream
+func Stream(name string, progress chan *spider.Progress, writer io.Writer, options Options) chan *spider.Progress {
+	reporter, _ := Get(name)
+	if streamer, ok := reporter.(Streamer); ok {
+		return streamer.Stream(progress, writer, options)
+	}
+
+	return progress
+}
+
+// Files returns additional files of the reporter keyed by their names,
+// like parts of the sitemap which is too big for one file
+func Files(name string, data *spider.Result, options Options) (map[string]string, error) {
+	reporter, _ := Get(name)
+	filer, ok := reporter.(Filer)
+	if data == nil || ok == false {
+		return nil, nil
 	}
 
-	return "", errors.New(name + " reporter doesn't exist")
+	return filer.Files(data, options)
 }
@@ -1,6 +1,8 @@
 package reporters_test
 
 import (
+	"fmt"
+	"io"
 	"io/ioutil"
 
 	"bou.ke/monkey"
@@ -8,12 +10,67 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
-	. "github.com/markelog/map/reporters"
+	"github.com/markelog/map/reporters"
 	"github.com/markelog/map/reporters/json"
 	"github.com/markelog/map/spider"
 )
 
+// titles is the test reporter
+type titles struct{}
+
+func (titles) Name() string {
+	return "titles"
+}
+
+func (titles) Write(writer io.Writer, data *spider.Result, options reporters.Options) error {
+	_, err := fmt.Fprint(writer, data.Name+options.Mode)
+
+	return err
+}
+
 var _ = Describe("reporters", func() {
+	Describe("Register", func() {
+		It("Registers the reporter", func() {
+			reporters.Register(titles{})
+
+			Expect(reporters.Exist("titles")).To(Equal(true))
+			Expect(reporters.Names()).To(ContainElement("titles"))
+
+			result, err := reporters.Execute("titles", &spider.Result{Name: "Home"}, reporters.Options{Mode: "!"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("Home!"))
+		})
+
+		It("Panics if reporter is already registered", func() {
+			Expect(func() {
+				reporters.Register(titles{})
+			}).To(Panic())
+		})
+	})
+
+	Describe("Names", func() {
+		It("Lists the built-in reporters", func() {
+			Expect(reporters.Names()).To(ContainElement("json"))
+			Expect(reporters.Names()).To(ContainElement("ndjson"))
+			Expect(reporters.Names()).To(ContainElement("sitemap"))
+		})
+	})
+
+	Describe("Streams", func() {
+		It("Finds out if reporter is able to stream", func() {
+			Expect(reporters.Streams("ndjson")).To(Equal(true))
+			Expect(reporters.Streams("json")).To(Equal(false))
+			Expect(reporters.Streams("nope")).To(Equal(false))
+		})
+
+		It("Passes the progress untouched for other reporters", func() {
+			progress := make(chan *spider.Progress)
+
+			Expect(reporters.Stream("json", progress, ioutil.Discard, reporters.Options{})).To(Equal(progress))
+		})
+	})
+
 	Describe("Execute", func() {
 		var (
 			jsonExecuted = false
@@ -32,31 +89,47 @@ var _ = Describe("reporters", func() {
 		})
 
 		It("Executes json reporter", func() {
-			Execute("json", &spider.Result{})
+			reporters.Execute("json", &spider.Result{}, reporters.Options{})
 			Expect(jsonExecuted).To(Equal(true))
 		})
 
 		It("Does not executes json reporter", func() {
-			Execute("yaml", &spider.Result{})
+			reporters.Execute("yaml", &spider.Result{}, reporters.Options{})
 
 			Expect(jsonExecuted).To(Equal(false))
 		})
 
 		It("Returns error if reporter doesn't exist", func() {
-			str, err := Execute("nope", &spider.Result{})
+			str, err := reporters.Execute("nope", &spider.Result{}, reporters.Options{})
 
 			Expect(str).To(Equal(""))
 			Expect(err).To(HaveOccurred())
 		})
 	})
 
+	Describe("Files", func() {
+		It("Returns nothing for one file reporters", func() {
+			files, err := reporters.Files("json", &spider.Result{}, reporters.Options{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(BeNil())
+		})
+
+		It("Returns nothing for small sitemap", func() {
+			files, err := reporters.Files("sitemap", &spider.Result{URL: "http://example.com"}, reporters.Options{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(BeNil())
+		})
+	})
+
 	Describe("Exist", func() {
 		It("Checks if reporter exists", func() {
-			Expect(Exist("json")).To(Equal(true))
+			Expect(reporters.Exist("json")).To(Equal(true))
 		})
 
 		It("Checks if reporter does not exists", func() {
-			Expect(Exist("nope")).To(Equal(false))
+			Expect(reporters.Exist("nope")).To(Equal(false))
 		})
 	})
 })
@@ -0,0 +1,183 @@
+// Package sitemap provides the sitemaps.org reporter
+package sitemap
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-errors/errors"
+
+	protocol "github.com/markelog/map/sitemap"
+	"github.com/markelog/map/spider"
+)
+
+const (
+	// MaxURLs is the amount of urls one sitemap might have
+	MaxURLs = 50000
+
+	// MaxSize is the size in bytes one sitemap might have
+	MaxSize = protocol.MaxSize
+)
+
+// Size of the <urlset> wrapper with the xml header
+var wrapperSize = len(xml.Header) + len(`<urlset xmlns="`+protocol.Namespace+`"></urlset>`)
+
+// Execute sitemap reporter, returns the sitemap or, if it's too big,
+// sitemap index which refers to the Parts as they would be placed at the root of the site
+func Execute(data *spider.Result) (string, error) {
+	parts, err := Parts(data)
+	if err != nil {
+		return "", err
+	}
+
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+
+	root, err := url.Parse(data.URL)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	index := &protocol.Index{
+		Namespace: protocol.Namespace,
+	}
+
+	for i := range parts {
+		location := &url.URL{
+			Scheme: root.Scheme,
+			Host:   root.Host,
+			Path:   "/" + PartName(i),
+		}
+
+		index.Sitemaps = append(index.Sitemaps, &protocol.URL{
+			Location: location.String(),
+		})
+	}
+
+	return marshal(index)
+}
+
+// PartName returns file name of the part
+func PartName(i int) string {
+	return "sitemap-" + strconv.Itoa(i+1) + ".xml"
+}
+
+// Parts flattens the tree and splits it into the sitemaps,
+// there is only one if the map fits into the limits
+func Parts(data *spider.Result) (parts []string, err error) {
+	var (
+		set  = newSet()
+		size = wrapperSize
+	)
+
+	for _, entry := range entries(data) {
+		encoded, err := xml.Marshal(entry)
+		if err != nil {
+			return nil, errors.New(err)
+		}
+
+		full := len(set.URLs) == MaxURLs || size+len(encoded) > MaxSize
+		if full && len(set.URLs) > 0 {
+			part, err := marshal(set)
+			if err != nil {
+				return nil, err
+			}
+
+			parts = append(parts, part)
+			set = newSet()
+			size = wrapperSize
+		}
+
+		set.URLs = append(set.URLs, entry)
+		size += len(encoded)
+	}
+
+	part, err := marshal(set)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(parts, part), nil
+}
+
+// entries flattens the tree into the sitemap entries, without broken and repeated ones,
+// sitemap can't have the pages of the other hosts, so they are left out as well
+func entries(data *spider.Result) (result []*protocol.URL) {
+	var (
+		broken = map[string]bool{}
+		seen   = map[string]bool{}
+		pages  = spider.Flatten(data)
+		root   = host(data.URL)
+	)
+
+	for _, page := range pages {
+		for _, link := range page.Broken {
+			broken[link.URL] = true
+		}
+	}
+
+	for _, page := range pages {
+		if broken[page.URL] || seen[page.URL] || host(page.URL) != root {
+			continue
+		}
+		seen[page.URL] = true
+
+		entry := &protocol.URL{
+			Location: page.URL,
+		}
+
+		if page.Response != nil {
+			entry.LastModified = lastModified(page.Response.LastModified)
+		}
+
+		result = append(result, entry)
+	}
+
+	return
+}
+
+// host of the url, empty if it can't be parsed
+func host(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Host
+}
+
+// lastModified converts "Last-Modified" header into the W3C datetime
+func lastModified(header string) string {
+	if len(header) == 0 {
+		return ""
+	}
+
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return ""
+	}
+
+	return date.UTC().Format(time.RFC3339)
+}
+
+// newSet returns empty url set
+func newSet() *protocol.URLSet {
+	return &protocol.URLSet{
+		Namespace: protocol.Namespace,
+	}
+}
+
+// marshal serializes the document with the xml header,
+// without indentation since size of the sitemap is limited
+func marshal(document interface{}) (string, error) {
+	result, err := xml.Marshal(document)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	return xml.Header + string(result), nil
+}
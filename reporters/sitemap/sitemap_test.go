@@ -0,0 +1,66 @@
+package sitemap_test
+
+import (
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/reporters/sitemap"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("reporters", func() {
+	Describe("Sitemap", func() {
+		It("Executes sitemap reporter", func() {
+			expected := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+				`<url><loc>http://example.com</loc><lastmod>2018-01-02T03:04:05Z</lastmod></url>` +
+				`<url><loc>http://example.com/a</loc></url>` +
+				`</urlset>`
+
+			result, err := Execute(&spider.Result{
+				URL: "http://example.com",
+				Response: &spider.Response{
+					LastModified: "Tue, 02 Jan 2018 03:04:05 GMT",
+				},
+				Broken: []*spider.Broken{
+					{URL: "http://example.com/broken"},
+				},
+				Children: []*spider.Result{
+					{URL: "http://example.com/a"},
+					{URL: "http://example.com/broken"},
+					{URL: "http://example.com/a"},
+					{URL: "http://example.net/other"},
+				},
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		})
+
+		It("Splits big sitemap into parts", func() {
+			data := &spider.Result{URL: "http://example.com"}
+			for i := 0; i < MaxURLs; i++ {
+				data.Children = append(data.Children, &spider.Result{
+					URL: "http://example.com/" + strconv.Itoa(i),
+				})
+			}
+
+			parts, err := Parts(data)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parts).To(HaveLen(2))
+			Expect(strings.Count(parts[0], "<url>")).To(Equal(MaxURLs))
+			Expect(strings.Count(parts[1], "<url>")).To(Equal(1))
+
+			index, err := Execute(data)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(index).To(ContainSubstring("<sitemapindex"))
+			Expect(index).To(ContainSubstring("<loc>http://example.com/sitemap-1.xml</loc>"))
+			Expect(index).To(ContainSubstring("<loc>http://example.com/sitemap-2.xml</loc>"))
+		})
+	})
+})
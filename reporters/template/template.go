@@ -0,0 +1,153 @@
+// Package template provides the reporter which renders
+// the user defined text/template
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/go-errors/errors"
+
+	"github.com/markelog/map/spider"
+)
+
+// markdown special characters
+var markdown = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"{", `\{`,
+	"}", `\}`,
+	"[", `\[`,
+	"]", `\]`,
+	"(", `\(`,
+	")", `\)`,
+	"#", `\#`,
+	"+", `\+`,
+	"-", `\-`,
+	".", `\.`,
+	"!", `\!`,
+	"|", `\|`,
+	"<", `\<`,
+	">", `\>`,
+)
+
+// Execute template reporter with the template file
+func Execute(data *spider.Result, path string) (string, error) {
+	if len(path) == 0 {
+		return "", errors.New(`Template reporter needs the template file, use "template" flag`)
+	}
+
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	return Render(data, string(text))
+}
+
+// Render the template with the result
+func Render(data *spider.Result, text string) (string, error) {
+	var (
+		buffer = &bytes.Buffer{}
+		report = template.New("report")
+	)
+
+	_, err := report.Funcs(Funcs(report)).Parse(text)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	err = report.Execute(buffer, data)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	return buffer.String(), nil
+}
+
+// Funcs are the helpers available in the template
+func Funcs(report *template.Template) template.FuncMap {
+	return template.FuncMap{
+		// Recursion
+		"include": func(name string, data interface{}) (string, error) {
+			buffer := &bytes.Buffer{}
+			err := report.ExecuteTemplate(buffer, name, data)
+
+			return buffer.String(), err
+		},
+		"dict":   dict,
+		"add":    func(a, b int) int { return a + b },
+		"repeat": func(count int, text string) string { return strings.Repeat(text, count) },
+		"indent": indent,
+
+		// Flattening
+		"flatten": spider.Flatten,
+
+		// URL parsing
+		"parseURL": func(link string) (*url.URL, error) {
+			result, err := url.Parse(link)
+			if err != nil {
+				return nil, errors.New(err)
+			}
+
+			return result, nil
+		},
+
+		// Escaping
+		"xml": func(text string) (string, error) {
+			buffer := &bytes.Buffer{}
+			err := xml.EscapeText(buffer, []byte(text))
+
+			return buffer.String(), err
+		},
+		"json": func(data interface{}) (string, error) {
+			buffer := &bytes.Buffer{}
+			encoder := json.NewEncoder(buffer)
+			encoder.SetEscapeHTML(false)
+			err := encoder.Encode(data)
+
+			return strings.TrimSuffix(buffer.String(), "\n"), err
+		},
+		"markdown": markdown.Replace,
+
+		// Strings
+		"join":  strings.Join,
+		"trim":  strings.TrimSpace,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+}
+
+// dict makes the map out of the key and value pairs,
+// so the template could pass more than one value
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict needs even number of arguments")
+	}
+
+	result := map[string]interface{}{}
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if ok == false {
+			return nil, errors.New("dict keys have to be strings")
+		}
+
+		result[key] = pairs[i+1]
+	}
+
+	return result, nil
+}
+
+// indent every line of the text
+func indent(count int, text string) string {
+	prefix := strings.Repeat(" ", count)
+
+	return prefix + strings.Replace(text, "\n", "\n"+prefix, -1)
+}
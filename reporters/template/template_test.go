@@ -0,0 +1,113 @@
+package template_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/reporters/template"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("reporters", func() {
+	Describe("Template", func() {
+		var data *spider.Result
+
+		BeforeEach(func() {
+			data = &spider.Result{
+				URL:  "http://example.com",
+				Name: "Home & away",
+				Children: []*spider.Result{
+					{
+						URL:  "http://example.com/a",
+						Name: "[A]",
+						Children: []*spider.Result{
+							{URL: "http://example.net/b", Name: "B"},
+						},
+					},
+				},
+			}
+		})
+
+		It("Executes the template file", func() {
+			file, _ := ioutil.TempFile("", "map")
+			defer os.Remove(file.Name())
+
+			file.WriteString("{{.Name}}")
+			file.Close()
+
+			result, err := Execute(data, file.Name())
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("Home & away"))
+		})
+
+		It("Needs the template file", func() {
+			_, err := Execute(data, "")
+			Expect(err).To(HaveOccurred())
+
+			_, err = Execute(data, "/nope/nope/nope")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Returns the parsing errors", func() {
+			_, err := Render(data, "{{.Name")
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Flattens the tree", func() {
+			result, err := Render(data, `{{range flatten .}}{{repeat .Depth "  "}}- [{{markdown .Name}}]({{.URL}}) {{.Parent}}
+{{end}}`)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(`- [Home & away](http://example.com) 
+  - [\[A\]](http://example.com/a) http://example.com
+    - [B](http://example.net/b) http://example.com/a
+`))
+		})
+
+		It("Recurses", func() {
+			result, err := Render(data, `{{define "page"}}<page url="{{xml .URL}}" title="{{xml .Name}}">
+{{- range .Children}}
+{{indent 2 (include "page" .)}}
+{{- end}}
+</page>{{end}}{{template "page" .}}`)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(`<page url="http://example.com" title="Home &amp; away">
+  <page url="http://example.com/a" title="[A]">
+    <page url="http://example.net/b" title="B">
+    </page>
+  </page>
+</page>`))
+		})
+
+		It("Parses the urls", func() {
+			result, _ := Render(data, `{{range flatten .}}{{(parseURL .URL).Host}} {{end}}`)
+
+			Expect(result).To(Equal("example.com example.com example.net "))
+		})
+
+		It("Passes more than one value", func() {
+			result, err := Render(
+				data,
+				`{{define "link"}}{{.prefix}}{{.page.URL}}{{end}}{{template "link" dict "prefix" "> " "page" .}}`,
+			)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("> http://example.com"))
+
+			_, err = Render(data, `{{dict "prefix"}}`)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Escapes json", func() {
+			result, _ := Render(data, `{{json .Name}}`)
+
+			Expect(result).To(Equal(`"Home & away"`))
+		})
+	})
+})
@@ -0,0 +1,106 @@
+// Package tree provides the reporter which draws the tree
+// of the pages like the "tree" command
+package tree
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/markelog/map/spider"
+)
+
+// Options of the tree
+type Options struct {
+	// Assets shows the asset counts of the pages
+	Assets bool
+
+	// Broken shows the broken links of the pages
+	Broken bool
+
+	// ASCII draws the tree without unicode characters
+	ASCII bool
+}
+
+// branches of the tree
+type branches struct {
+	middle, last, pipe, space, broken string
+}
+
+var (
+	unicode = branches{"├── ", "└── ", "│   ", "    ", "✗ "}
+	ascii   = branches{"|-- ", "`-- ", "|   ", "    ", "x "}
+)
+
+// line of the tree
+type line struct {
+	text     string
+	children []*line
+}
+
+// Execute tree reporter
+func Execute(data *spider.Result, options Options) (string, error) {
+	var (
+		buffer = &bytes.Buffer{}
+		style  = unicode
+	)
+
+	if options.ASCII {
+		style = ascii
+	}
+
+	root := build(data, options, style)
+	buffer.WriteString(root.text)
+
+	draw(buffer, root.children, "", style)
+
+	return buffer.String(), nil
+}
+
+// build the lines of the page and its children
+func build(data *spider.Result, options Options, style branches) *line {
+	result := &line{text: label(data)}
+
+	if options.Assets {
+		if summary := data.AssetsSummary(); len(summary) > 0 {
+			result.text += " [" + summary + "]"
+		}
+	}
+
+	for _, child := range data.Children {
+		result.children = append(result.children, build(child, options, style))
+	}
+
+	if options.Broken {
+		for _, link := range data.Broken {
+			result.children = append(result.children, &line{
+				text: style.broken + link.URL + " (" + link.Reason() + ")",
+			})
+		}
+	}
+
+	return result
+}
+
+// draw the lines with their branches
+func draw(buffer *bytes.Buffer, lines []*line, prefix string, style branches) {
+	for i, item := range lines {
+		branch, next := style.middle, style.pipe
+		if i == len(lines)-1 {
+			branch, next = style.last, style.space
+		}
+
+		buffer.WriteString("\n" + prefix + branch + item.text)
+
+		draw(buffer, item.children, prefix+next, style)
+	}
+}
+
+// label of the page
+func label(data *spider.Result) string {
+	name := strings.TrimSpace(data.Name)
+	if len(name) == 0 {
+		return data.URL
+	}
+
+	return name + " (" + data.URL + ")"
+}
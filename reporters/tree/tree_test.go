@@ -0,0 +1,82 @@
+package tree_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/markelog/map/collect"
+	. "github.com/markelog/map/reporters/tree"
+	"github.com/markelog/map/spider"
+)
+
+var _ = Describe("reporters", func() {
+	Describe("Tree", func() {
+		var data *spider.Result
+
+		BeforeEach(func() {
+			data = &spider.Result{
+				URL:  "http://example.com",
+				Name: "Home",
+				Assets: map[string][]*collect.Asset{
+					"images": {{URL: "http://example.com/a.png"}, {URL: "http://example.com/b.png"}},
+					"styles": {{URL: "http://example.com/main.css"}},
+					"video":  {},
+				},
+				Broken: []*spider.Broken{
+					{URL: "http://example.com/nope", StatusCode: 404},
+				},
+				Children: []*spider.Result{
+					{
+						URL:  "http://example.com/a",
+						Name: "A",
+						Children: []*spider.Result{
+							{URL: "http://example.com/b"},
+						},
+						Broken: []*spider.Broken{
+							{URL: "http://nope.example", Class: spider.ClassDNS},
+						},
+					},
+					{URL: "http://example.com/c", Name: "C"},
+				},
+			}
+		})
+
+		It("Executes tree reporter", func() {
+			expected := `Home (http://example.com)
+├── A (http://example.com/a)
+│   └── http://example.com/b
+└── C (http://example.com/c)`
+
+			result, err := Execute(data, Options{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		})
+
+		It("Shows the assets and the broken links", func() {
+			expected := `Home (http://example.com) [2 images, 1 styles]
+├── A (http://example.com/a)
+│   ├── http://example.com/b
+│   └── ✗ http://nope.example (dns)
+├── C (http://example.com/c)
+└── ✗ http://example.com/nope (404)`
+
+			result, _ := Execute(data, Options{Assets: true, Broken: true})
+
+			Expect(result).To(Equal(expected))
+		})
+
+		It("Draws with ascii", func() {
+			expected := "Home (http://example.com)\n" +
+				"|-- A (http://example.com/a)\n" +
+				"|   |-- http://example.com/b\n" +
+				"|   `-- x http://nope.example (dns)\n" +
+				"|-- C (http://example.com/c)\n" +
+				"`-- x http://example.com/nope (404)"
+
+			result, _ := Execute(data, Options{Broken: true, ASCII: true})
+
+			Expect(result).To(Equal(expected))
+		})
+	})
+})
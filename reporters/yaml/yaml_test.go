@@ -22,5 +22,36 @@ url: ""
 
 			Expect(result).To(Equal(expected))
 		})
+
+		It("Includes the response", func() {
+			expected := `assets: null
+broken: null
+children: null
+links: null
+name: test
+response:
+  contentLength: 10
+  contentType: text/html
+  redirects:
+  - http://example.com/new
+  statusCode: 301
+  time: 5
+  url: http://example.com/new
+url: ""
+`
+			result, _ := Execute(&spider.Result{
+				Name: "test",
+				Response: &spider.Response{
+					StatusCode:    301,
+					Redirects:     []string{"http://example.com/new"},
+					URL:           "http://example.com/new",
+					ContentType:   "text/html",
+					ContentLength: 10,
+					Time:          5,
+				},
+			})
+
+			Expect(result).To(Equal(expected))
+		})
 	})
 })
@@ -0,0 +1,144 @@
+// Package robots fetches robots.txt of the hosts
+// and checks what is allowed to crawl there
+package robots
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/temoto/robotstxt"
+)
+
+// Robots settings
+type Robots struct {
+	agent   string
+	headers http.Header
+	client  *http.Client
+	context context.Context
+
+	mutex *sync.RWMutex
+	hosts map[string]*robotstxt.RobotsData
+}
+
+// New returns new instance of Robots for the user agent
+func New(agent string, client *http.Client) *Robots {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Robots{
+		agent:   agent,
+		headers: http.Header{},
+		client:  client,
+		context: context.Background(),
+
+		mutex: &sync.RWMutex{},
+		hosts: make(map[string]*robotstxt.RobotsData),
+	}
+}
+
+// SetHeaders sets additional headers for robots.txt requests
+func (robots *Robots) SetHeaders(headers http.Header) {
+	robots.headers = headers
+}
+
+// SetContext sets the context of robots.txt requests, they stop once it's done
+func (robots *Robots) SetContext(ctx context.Context) {
+	robots.context = ctx
+}
+
+// Fetch requests and parses robots.txt of the host,
+// if it can't be fetched everything is allowed
+func (robots *Robots) Fetch(scheme, host string) error {
+	address := scheme + "://" + host + "/robots.txt"
+
+	request, err := http.NewRequestWithContext(robots.context, "GET", address, nil)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	for name, values := range robots.headers {
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
+	}
+	request.Header.Set("User-Agent", robots.agent)
+
+	response, err := robots.client.Do(request)
+	if err != nil {
+		return errors.New(err)
+	}
+	defer response.Body.Close()
+
+	data, err := robotstxt.FromResponse(response)
+	if err != nil {
+		return errors.New(err)
+	}
+
+	robots.mutex.Lock()
+	robots.hosts[host] = data
+	robots.mutex.Unlock()
+
+	return nil
+}
+
+// Allowed checks if link is allowed to be crawled,
+// links of unknown hosts are always allowed
+func (robots *Robots) Allowed(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return true
+	}
+
+	group := robots.group(parsed.Host)
+	if group == nil {
+		return true
+	}
+
+	path := parsed.EscapedPath()
+	if len(parsed.RawQuery) > 0 {
+		path += "?" + parsed.RawQuery
+	}
+
+	return group.Test(path)
+}
+
+// Delay returns "Crawl-delay" of the host for our user agent
+func (robots *Robots) Delay(host string) time.Duration {
+	group := robots.group(host)
+	if group == nil {
+		return 0
+	}
+
+	return group.CrawlDelay
+}
+
+// Sitemaps returns "Sitemap" entries of the host
+func (robots *Robots) Sitemaps(host string) []string {
+	robots.mutex.RLock()
+	defer robots.mutex.RUnlock()
+
+	data, ok := robots.hosts[host]
+	if ok == false {
+		return nil
+	}
+
+	return data.Sitemaps
+}
+
+// group finds rules group of the host for our user agent
+func (robots *Robots) group(host string) *robotstxt.Group {
+	robots.mutex.RLock()
+	defer robots.mutex.RUnlock()
+
+	data, ok := robots.hosts[host]
+	if ok == false {
+		return nil
+	}
+
+	return data.FindGroup(robots.agent)
+}
@@ -0,0 +1,119 @@
+package robots_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/robots"
+)
+
+const rules = `User-agent: map
+Disallow: /private
+Crawl-delay: 2
+
+User-agent: *
+Disallow: /
+`
+
+var _ = Describe("robots", func() {
+	var (
+		ts    *httptest.Server
+		host  string
+		agent string
+		txt   string
+	)
+
+	BeforeEach(func() {
+		txt = rules
+
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			agent = r.UserAgent()
+
+			if r.URL.Path != "/robots.txt" {
+				w.WriteHeader(404)
+				return
+			}
+
+			io.WriteString(w, txt)
+		}))
+
+		parsed, _ := url.Parse(ts.URL)
+		host = parsed.Host
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	Describe("Fetch", func() {
+		It("Sends the user agent", func() {
+			Expect(New("map", nil).Fetch("http", host)).ToNot(HaveOccurred())
+			Expect(agent).To(Equal("map"))
+		})
+
+		It("Returns error when host is unreachable", func() {
+			Expect(New("map", nil).Fetch("http", "127.0.0.1:1")).To(HaveOccurred())
+		})
+
+		It("Returns error when context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			robots := New("map", nil)
+			robots.SetContext(ctx)
+
+			Expect(robots.Fetch("http", host)).To(HaveOccurred())
+		})
+	})
+
+	Describe("Allowed", func() {
+		It("Checks the rules of our user agent", func() {
+			robots := New("map", nil)
+			robots.Fetch("http", host)
+
+			Expect(robots.Allowed(ts.URL + "/public")).To(Equal(true))
+			Expect(robots.Allowed(ts.URL + "/private/page")).To(Equal(false))
+		})
+
+		It("Checks the rules of other user agents", func() {
+			robots := New("other", nil)
+			robots.Fetch("http", host)
+
+			Expect(robots.Allowed(ts.URL + "/public")).To(Equal(false))
+		})
+
+		It("Allows everything on unknown hosts", func() {
+			robots := New("other", nil)
+
+			Expect(robots.Allowed(ts.URL + "/public")).To(Equal(true))
+		})
+
+		It("Allows everything if there is no robots.txt", func() {
+			txt = ""
+			robots := New("other", nil)
+			robots.Fetch("http", host)
+
+			Expect(robots.Allowed(ts.URL + "/public")).To(Equal(true))
+		})
+	})
+
+	Describe("Delay", func() {
+		It("Returns crawl delay of our user agent", func() {
+			robots := New("map", nil)
+			robots.Fetch("http", host)
+
+			Expect(robots.Delay(host)).To(Equal(2 * time.Second))
+		})
+
+		It("Returns nothing on unknown hosts", func() {
+			Expect(New("map", nil).Delay(host)).To(Equal(time.Duration(0)))
+		})
+	})
+})
@@ -0,0 +1,238 @@
+// Package sitemap fetches and parses sitemaps.org documents,
+// both url sets and sitemap indexes, plain or gzipped
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// Namespace of the sitemaps.org protocol
+const Namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// maxNesting limits how deep sitemap indexes might refer to each other
+const maxNesting = 5
+
+// MaxSize is the size in bytes one sitemap might have, uncompressed
+const MaxSize = 50 * 1024 * 1024
+
+// URLSet is the <urlset> document
+type URLSet struct {
+	XMLName   xml.Name `xml:"urlset"`
+	Namespace string   `xml:"xmlns,attr,omitempty"`
+	URLs      []*URL   `xml:"url"`
+}
+
+// URL is the <url> entry of the <urlset>
+type URL struct {
+	Location     string `xml:"loc"`
+	LastModified string `xml:"lastmod,omitempty"`
+}
+
+// Index is the <sitemapindex> document
+type Index struct {
+	XMLName   xml.Name `xml:"sitemapindex"`
+	Namespace string   `xml:"xmlns,attr,omitempty"`
+	Sitemaps  []*URL   `xml:"sitemap"`
+}
+
+// Errors of the nested sitemaps which couldn't be fetched,
+// locations of the other ones are returned along with them
+type Errors []error
+
+// Error joins the messages of the errors
+func (errs Errors) Error() string {
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Sitemap settings
+type Sitemap struct {
+	agent   string
+	headers http.Header
+	client  *http.Client
+}
+
+// New returns new instance of Sitemap
+func New(agent string, client *http.Client) *Sitemap {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Sitemap{
+		agent:   agent,
+		headers: http.Header{},
+		client:  client,
+	}
+}
+
+// SetHeaders sets additional headers for sitemap requests
+func (sitemap *Sitemap) SetHeaders(headers http.Header) {
+	sitemap.headers = headers
+}
+
+// Fetch requests the sitemap and returns all its locations,
+// nested sitemaps of the index are fetched as well, if some
+// of them fail, locations of the others come with the Errors,
+// requests stop once the context is done
+func (sitemap *Sitemap) Fetch(ctx context.Context, address string) ([]string, error) {
+	nested := Errors{}
+
+	locations, err := sitemap.fetch(ctx, address, map[string]bool{}, 0, &nested)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nested) > 0 {
+		return locations, nested
+	}
+
+	return locations, nil
+}
+
+// fetch requests the sitemap while keeping track of visited indexes
+// and of the nested sitemaps which failed
+func (sitemap *Sitemap) fetch(
+	ctx context.Context,
+	address string,
+	visited map[string]bool,
+	nesting int,
+	nested *Errors,
+) (locations []string, err error) {
+	if visited[address] || nesting > maxNesting {
+		return
+	}
+	visited[address] = true
+
+	body, err := sitemap.get(ctx, address)
+	if err != nil {
+		return
+	}
+
+	set, index, err := Parse(body)
+	if err != nil {
+		return nil, errors.New("Can't parse " + address + ": " + err.Error())
+	}
+
+	if set != nil {
+		for _, entry := range set.URLs {
+			locations = append(locations, entry.Location)
+		}
+
+		return
+	}
+
+	for _, entry := range index.Sitemaps {
+		result, err := sitemap.fetch(ctx, entry.Location, visited, nesting+1, nested)
+		if err != nil {
+			*nested = append(*nested, err)
+			continue
+		}
+
+		locations = append(locations, result...)
+	}
+
+	return
+}
+
+// get requests the address and returns its body
+func (sitemap *Sitemap) get(ctx context.Context, address string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", address, nil)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	for name, values := range sitemap.headers {
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
+	}
+	request.Header.Set("User-Agent", sitemap.agent)
+
+	response, err := sitemap.client.Do(request)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 400 {
+		return nil, errors.New("Can't fetch " + address + ": " + response.Status)
+	}
+
+	return readAll(response.Body)
+}
+
+// Parse parses either url set or index, gzipped or not
+func Parse(body []byte) (set *URLSet, index *Index, err error) {
+	body, err = gunzip(body)
+	if err != nil {
+		return
+	}
+
+	var root struct {
+		XMLName xml.Name
+	}
+
+	err = xml.Unmarshal(body, &root)
+	if err != nil {
+		return nil, nil, errors.New(err)
+	}
+
+	switch root.XMLName.Local {
+	case "urlset":
+		set = &URLSet{}
+		err = xml.Unmarshal(body, set)
+	case "sitemapindex":
+		index = &Index{}
+		err = xml.Unmarshal(body, index)
+	default:
+		err = errors.New("Unknown sitemap element <" + root.XMLName.Local + ">")
+	}
+
+	if err != nil {
+		return nil, nil, errors.New(err)
+	}
+
+	return
+}
+
+// gunzip decompresses the body if it is gzipped
+func gunzip(body []byte) ([]byte, error) {
+	if len(body) < 2 || body[0] != 0x1f || body[1] != 0x8b {
+		return body, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	defer reader.Close()
+
+	return readAll(reader)
+}
+
+// readAll reads no more than one sitemap might have
+func readAll(reader io.Reader) ([]byte, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(reader, MaxSize+1))
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	if len(body) > MaxSize {
+		return nil, errors.New("Sitemap is bigger than 50MB")
+	}
+
+	return body, nil
+}
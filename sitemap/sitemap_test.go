@@ -0,0 +1,169 @@
+package sitemap_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/sitemap"
+)
+
+const set = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>$URL/a</loc><lastmod>2018-01-01</lastmod></url>
+  <url><loc>$URL/b</loc></url>
+</urlset>`
+
+const index = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>$URL/set.xml</loc></sitemap>
+  <sitemap><loc>$URL/set.xml.gz</loc></sitemap>
+  <sitemap><loc>$URL/index.xml</loc></sitemap>
+</sitemapindex>`
+
+const brokenIndex = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>$URL/nope.xml</loc></sitemap>
+  <sitemap><loc>$URL/set.xml</loc></sitemap>
+  <sitemap><loc>$URL/broken.xml</loc></sitemap>
+</sitemapindex>`
+
+func compress(text string) []byte {
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+	writer.Write([]byte(text))
+	writer.Close()
+
+	return buffer.Bytes()
+}
+
+var _ = Describe("sitemap", func() {
+	Describe("Parse", func() {
+		It("Parses url set", func() {
+			result, idx, err := Parse([]byte(set))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(idx).To(BeNil())
+			Expect(result.URLs).To(HaveLen(2))
+			Expect(result.URLs[0].Location).To(Equal("$URL/a"))
+			Expect(result.URLs[0].LastModified).To(Equal("2018-01-01"))
+		})
+
+		It("Parses index", func() {
+			result, idx, err := Parse([]byte(index))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeNil())
+			Expect(idx.Sitemaps).To(HaveLen(3))
+			Expect(idx.Sitemaps[0].Location).To(Equal("$URL/set.xml"))
+		})
+
+		It("Parses gzipped document", func() {
+			result, _, err := Parse(compress(set))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.URLs).To(HaveLen(2))
+		})
+
+		It("Returns error for unknown document", func() {
+			_, _, err := Parse([]byte("<html></html>"))
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Returns error for too big gzipped document", func() {
+			_, _, err := Parse(compress(strings.Repeat(" ", MaxSize+1)))
+
+			Expect(err).To(MatchError("Sitemap is bigger than 50MB"))
+		})
+
+		It("Returns error for broken document", func() {
+			_, _, err := Parse([]byte("nope"))
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Fetch", func() {
+		var ts *httptest.Server
+
+		BeforeEach(func() {
+			ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				replace := func(text string) string {
+					return strings.Replace(text, "$URL", "http://"+r.Host, -1)
+				}
+
+				switch r.URL.Path {
+				case "/set.xml":
+					io.WriteString(w, replace(set))
+				case "/set.xml.gz":
+					w.Write(compress(replace(strings.Replace(set, "/a", "/c", 1))))
+				case "/index.xml":
+					io.WriteString(w, replace(index))
+				case "/broken-index.xml":
+					io.WriteString(w, replace(brokenIndex))
+				case "/broken.xml":
+					io.WriteString(w, "<html></html>")
+				default:
+					w.WriteHeader(404)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			ts.Close()
+		})
+
+		It("Fetches url set", func() {
+			locations, err := New("map", nil).Fetch(context.Background(), ts.URL+"/set.xml")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(locations).To(Equal([]string{ts.URL + "/a", ts.URL + "/b"}))
+		})
+
+		It("Fetches nested sitemaps of the index only once", func() {
+			locations, err := New("map", nil).Fetch(context.Background(), ts.URL+"/index.xml")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(locations).To(Equal([]string{
+				ts.URL + "/a",
+				ts.URL + "/b",
+				ts.URL + "/c",
+				ts.URL + "/b",
+			}))
+		})
+
+		It("Returns error for absent sitemap", func() {
+			_, err := New("map", nil).Fetch(context.Background(), ts.URL+"/nope.xml")
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Returns error when context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			locations, err := New("map", nil).Fetch(ctx, ts.URL+"/set.xml")
+
+			Expect(locations).To(BeNil())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Keeps the locations of the index if some of its sitemaps fail", func() {
+			locations, err := New("map", nil).Fetch(context.Background(), ts.URL+"/broken-index.xml")
+
+			Expect(locations).To(Equal([]string{ts.URL + "/a", ts.URL + "/b"}))
+			Expect(err).To(HaveLen(2))
+			Expect(err.Error()).To(ContainSubstring("Can't fetch " + ts.URL + "/nope.xml: 404 Not Found"))
+			Expect(err.Error()).To(ContainSubstring("Can't parse " + ts.URL + "/broken.xml"))
+		})
+	})
+})
@@ -0,0 +1,249 @@
+package spider
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/markelog/map/collect"
+)
+
+// Classes of the broken assets, besides the classes of the broken links
+const (
+	ClassEmpty       = "empty"
+	ClassContentType = "content-type"
+)
+
+// BrokenAsset is the asset which doesn't exist or isn't what it should be
+type BrokenAsset struct {
+	URL  string `json:"url"`
+	Kind string `json:"kind"`
+
+	StatusCode    int    `json:"statusCode,omitempty"`
+	ContentType   string `json:"contentType,omitempty"`
+	ContentLength int64  `json:"contentLength,omitempty"`
+
+	Class   string `json:"class"`
+	Message string `json:"message"`
+}
+
+// contentTypes are the expected content types of the assets by their kind,
+// either the whole type or the prefix which ends with the slash
+var contentTypes = map[string][]string{
+	"images": {"image/"},
+	"styles": {"text/css"},
+	"scripts": {
+		"application/javascript",
+		"application/ecmascript",
+		"application/x-javascript",
+		"text/javascript",
+		"text/ecmascript",
+	},
+
+	// Posters and subtitles are part of the video
+	"video": {"video/", "image/", "text/vtt", "application/ogg"},
+	"audio": {"audio/", "application/ogg"},
+	"fonts": {
+		"font/",
+		"application/font-woff",
+		"application/font-woff2",
+		"application/x-font-woff",
+		"application/x-font-ttf",
+		"application/x-font-otf",
+		"application/vnd.ms-fontobject",
+		"application/octet-stream",
+	},
+}
+
+// assetCheck is the cached result of the asset request
+type assetCheck struct {
+	done chan bool
+
+	statusCode    int
+	contentType   string
+	contentLength int64
+	err           error
+}
+
+// checkAssets requests every asset of the page, which wasn't requested before
+// and is allowed by robots.txt, and records the broken ones on the page
+func (spider *Spider) checkAssets(output *Result) {
+	if spider.assets == nil {
+		return
+	}
+
+	known := map[string]bool{}
+
+	for kind, assets := range output.Assets {
+		for _, asset := range assets {
+			if asset.Type != collect.AssetInternal && asset.Type != collect.AssetExternal {
+				continue
+			}
+
+			if known[kind+asset.URL] || isHTTP(asset.URL) == false {
+				continue
+			}
+
+			known[kind+asset.URL] = true
+
+			if spider.isAllowed(asset.URL) == false {
+				spider.skip(output, asset.URL, "robots.txt")
+				continue
+			}
+
+			spider.waitGroup.Add(1)
+			go func(kind, link string) {
+				defer spider.waitGroup.Done()
+
+				broken := newBrokenAsset(kind, link, spider.probe(spider.assets, link))
+				if broken == nil {
+					return
+				}
+
+				spider.mutex.Lock()
+				output.BrokenAssets = append(output.BrokenAssets, broken)
+				spider.mutex.Unlock()
+			}(kind, asset.URL)
+		}
+	}
+}
+
+// probe finds out if the asset or the external link is fine, the first page
+// which has the link requests it, the others wait for the cached result
+func (spider *Spider) probe(cache map[string]*assetCheck, link string) *assetCheck {
+	spider.mutex.Lock()
+	check, ok := cache[link]
+	if ok == false {
+		check = &assetCheck{done: make(chan bool)}
+		cache[link] = check
+	}
+	spider.mutex.Unlock()
+
+	if ok {
+		<-check.done
+		return check
+	}
+
+	defer close(check.done)
+
+	// Not every server supports HEAD
+	response, err := spider.requestAsset(http.MethodHead, link)
+	if err == nil && (response.StatusCode == http.StatusMethodNotAllowed ||
+		response.StatusCode == http.StatusNotImplemented) {
+		response, err = spider.requestAsset(http.MethodGet, link)
+	}
+
+	if err != nil {
+		check.err = err
+		return check
+	}
+
+	check.statusCode = response.StatusCode
+	check.contentLength = response.ContentLength
+	check.contentType, _, _ = mime.ParseMediaType(response.Header.Get("Content-Type"))
+
+	return check
+}
+
+// requestAsset makes the request without reading the body,
+// once there is a free slot for it, like the pages do
+func (spider *Spider) requestAsset(method, link string) (*http.Response, error) {
+	done, ok := spider.schedule(link)
+	if ok == false {
+		return nil, spider.context.Err()
+	}
+	defer done()
+
+	response, err := spider.fetch(method, link)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Body.Close()
+
+	return response, nil
+}
+
+// fetch makes the request with the headers of the crawl, outside of the collector
+func (spider *Spider) fetch(method, link string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(spider.context, method, link, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", spider.collector.UserAgent)
+	for name, values := range spider.headers {
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
+	}
+
+	return spider.client().Do(request)
+}
+
+// newBrokenAsset creates broken asset record out of the check,
+// nil if the asset is fine
+func newBrokenAsset(kind, link string, check *assetCheck) *BrokenAsset {
+	broken := &BrokenAsset{
+		URL:           link,
+		Kind:          kind,
+		StatusCode:    check.statusCode,
+		ContentType:   check.contentType,
+		ContentLength: check.contentLength,
+	}
+
+	if broken.ContentLength < 0 {
+		broken.ContentLength = 0
+	}
+
+	switch {
+	case check.err != nil:
+		broken.Class = classify(0, check.err)
+		broken.Message = check.err.Error()
+	case check.statusCode >= 400:
+		broken.Class = ClassHTTP
+		broken.Message = http.StatusText(check.statusCode)
+	case check.contentLength == 0:
+		broken.Class = ClassEmpty
+		broken.Message = "Asset is empty"
+	case isExpected(kind, check.contentType) == false:
+		broken.Class = ClassContentType
+		broken.Message = `Unexpected "` + check.contentType + `" content type`
+	default:
+		return nil
+	}
+
+	return broken
+}
+
+// isExpected checks the content type against the kind of the asset,
+// unknown kinds and absent content types are not checked
+func isExpected(kind, contentType string) bool {
+	expected, ok := contentTypes[kind]
+	if ok == false || len(contentType) == 0 {
+		return true
+	}
+
+	for _, item := range expected {
+		if item == contentType {
+			return true
+		}
+
+		if strings.HasSuffix(item, "/") && strings.HasPrefix(contentType, item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isHTTP checks if the link could be requested
+func isHTTP(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	return parsed.Scheme == "http" || parsed.Scheme == "https"
+}
@@ -0,0 +1,206 @@
+package spider_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/markelog/map/collect"
+	. "github.com/markelog/map/spider"
+)
+
+var _ = Describe("assets", func() {
+	var (
+		ts       *httptest.Server
+		requests map[string][]string
+		mutex    sync.Mutex
+	)
+
+	BeforeEach(func() {
+		requests = map[string][]string{}
+
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requests[r.URL.Path] = append(requests[r.URL.Path], r.Method)
+			mutex.Unlock()
+
+			serve := func(contentType, body string) {
+				w.Header().Set("Content-Type", contentType)
+				w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				w.WriteHeader(200)
+				io.WriteString(w, body)
+			}
+
+			switch r.URL.Path {
+			case "/robots.txt":
+				serve("text/plain", "User-agent: *\nDisallow: /private\n")
+			case "/":
+				serve("text/html", `<html>
+					<link rel="stylesheet" href="/style.css">
+					<script src="/wrong.js"></script>
+					<img src="/ok.png">
+					<img src="/missing.png">
+					<img src="/empty.png">
+					<img src="data:image/png;base64,iVBO">
+					<img srcset="/ok.png 1x, /large.png 2x" sizes="100vw">
+					<img src="/private/secret.png">
+					<a href="/a">a</a>
+				</html>`)
+			case "/a":
+				serve("text/html", `<html><img src="/missing.png"><img src="/missing.png"></html>`)
+			case "/ok.png", "/large.png":
+				serve("image/png", "png")
+			case "/empty.png":
+				serve("image/png", "")
+			case "/wrong.js":
+				serve("text/html; charset=utf-8", "<html></html>")
+			case "/style.css":
+				if r.Method == http.MethodHead {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+
+				serve("text/css", "body {}")
+			default:
+				w.WriteHeader(404)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	crawl := func(options ...Option) *Result {
+		crawler := NewWithOptions(ts.URL, options...)
+
+		for range crawler.Crawl() {
+		}
+
+		result, _ := crawler.Get()
+
+		return result
+	}
+
+	It("Keeps the descriptors of the images", func() {
+		result := crawl()
+
+		Expect(result.Assets["images"]).To(ContainElement(&collect.Asset{
+			URL:        ts.URL + "/ok.png",
+			Type:       collect.AssetInternal,
+			Descriptor: "1x",
+			Sizes:      "100vw",
+		}))
+		Expect(result.Assets["images"]).To(ContainElement(&collect.Asset{
+			URL:        ts.URL + "/large.png",
+			Type:       collect.AssetInternal,
+			Descriptor: "2x",
+			Sizes:      "100vw",
+		}))
+	})
+
+	It("Does not check the assets by default", func() {
+		result := crawl()
+
+		Expect(result.BrokenAssets).To(BeNil())
+		Expect(requests).ToNot(HaveKey("/ok.png"))
+	})
+
+	It("Records the broken assets", func() {
+		result := crawl(WithCheckAssets(true))
+
+		// Sorted by the url, not by the order of the responses
+		Expect(result.BrokenAssets).To(Equal([]*BrokenAsset{
+			{
+				URL:         ts.URL + "/empty.png",
+				Kind:        "images",
+				StatusCode:  200,
+				ContentType: "image/png",
+				Class:       ClassEmpty,
+				Message:     "Asset is empty",
+			},
+			{
+				URL:        ts.URL + "/missing.png",
+				Kind:       "images",
+				StatusCode: 404,
+				Class:      ClassHTTP,
+				Message:    "Not Found",
+			},
+			{
+				URL:           ts.URL + "/wrong.js",
+				Kind:          "scripts",
+				StatusCode:    200,
+				ContentType:   "text/html",
+				ContentLength: 13,
+				Class:         ClassContentType,
+				Message:       `Unexpected "text/html" content type`,
+			},
+		}))
+	})
+
+	It("Does not request the assets disallowed by robots.txt", func() {
+		result := crawl(WithCheckAssets(true))
+
+		Expect(requests).ToNot(HaveKey("/private/secret.png"))
+		Expect(result.Skipped).To(Equal([]*Skipped{
+			{URL: ts.URL + "/private/secret.png", Reason: "robots.txt"},
+		}))
+	})
+
+	It("Waits for the delay between the asset requests", func() {
+		var (
+			times []time.Time
+			delay = 30 * time.Millisecond
+		)
+
+		site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			times = append(times, time.Now())
+			mutex.Unlock()
+
+			// GET which comes after HEAD has to wait as well
+			if r.URL.Path == "/2.png" && r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+
+			w.WriteHeader(200)
+			io.WriteString(w, `<html><img src="/1.png"><img src="/2.png"><img src="/3.png"></html>`)
+		}))
+		defer site.Close()
+
+		crawler := NewWithOptions(site.URL, WithCheckAssets(true), WithDelay(delay), WithIgnoreRobots(true))
+		for range crawler.Crawl() {
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		Expect(times).To(HaveLen(5))
+		for i := 1; i < len(times); i++ {
+			Expect(times[i].Sub(times[i-1])).To(BeNumerically(">=", delay))
+		}
+	})
+
+	It("Requests every asset once", func() {
+		result := crawl(WithCheckAssets(true))
+
+		Expect(result.Children[0].BrokenAssets).To(HaveLen(1))
+		Expect(result.Children[0].BrokenAssets[0].URL).To(Equal(ts.URL + "/missing.png"))
+
+		Expect(requests["/missing.png"]).To(Equal([]string{"HEAD"}))
+		Expect(requests["/ok.png"]).To(Equal([]string{"HEAD"}))
+	})
+
+	It("Falls back to GET", func() {
+		crawl(WithCheckAssets(true), WithStylesheets(false))
+
+		Expect(requests["/style.css"]).To(Equal([]string{"HEAD", "GET"}))
+	})
+})
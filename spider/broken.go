@@ -0,0 +1,172 @@
+package spider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gocolly/colly"
+)
+
+// Classes of the broken links
+const (
+	ClassHTTP       = "http"
+	ClassDNS        = "dns"
+	ClassTLS        = "tls"
+	ClassTimeout    = "timeout"
+	ClassConnection = "connection"
+	ClassUnknown    = "unknown"
+)
+
+// Broken link with the reason why it's broken
+type Broken struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Class      string `json:"class"`
+	Message    string `json:"message"`
+
+	// Text and CSS selector of the link which lead to it
+	Text     string `json:"text,omitempty"`
+	Selector string `json:"selector,omitempty"`
+}
+
+// UnmarshalJSON supports the maps which were saved when broken links were just the strings
+func (broken *Broken) UnmarshalJSON(data []byte) error {
+	var link string
+	if json.Unmarshal(data, &link) == nil {
+		broken.URL = link
+
+		return nil
+	}
+
+	// Prevent the recursion
+	type plain Broken
+
+	return json.Unmarshal(data, (*plain)(broken))
+}
+
+// Error describes the broken link, it's the error of the crawl if the root is broken
+func (broken *Broken) Error() string {
+	if broken.StatusCode != 0 {
+		return broken.URL + ": " + strconv.Itoa(broken.StatusCode) + " " + broken.Message
+	}
+
+	return broken.URL + ": " + broken.Message
+}
+
+// Reason why the link is broken, the status code if there is one
+func (broken *Broken) Reason() string {
+	if broken.StatusCode != 0 {
+		return strconv.Itoa(broken.StatusCode)
+	}
+
+	if len(broken.Class) > 0 {
+		return broken.Class
+	}
+
+	return broken.Message
+}
+
+// newBroken creates broken link record out of the failed response,
+// parent is nil for the root
+func newBroken(parent *Result, response *colly.Response, err error) *Broken {
+	link := response.Ctx.Get("url")
+	if len(link) == 0 {
+		link = response.Request.URL.String()
+	}
+
+	broken := &Broken{
+		URL:        link,
+		StatusCode: response.StatusCode,
+		Class:      classify(response.StatusCode, err),
+	}
+
+	if err != nil {
+		broken.Message = err.Error()
+	}
+
+	if parent == nil {
+		return broken
+	}
+
+	for _, anchor := range parent.anchors {
+		if anchor.URL == link {
+			broken.Text = anchor.Text
+			broken.Selector = anchor.Selector
+			break
+		}
+	}
+
+	return broken
+}
+
+// sortBroken sorts the broken links and assets of every page, they are
+// added in the order of the responses, which is different every time
+func (spider *Spider) sortBroken() {
+	for _, page := range Flatten(spider.Result) {
+		broken, assets := page.Broken, page.BrokenAssets
+
+		sort.SliceStable(broken, func(i, j int) bool {
+			return broken[i].URL < broken[j].URL
+		})
+
+		sort.SliceStable(assets, func(i, j int) bool {
+			if assets[i].URL == assets[j].URL {
+				return assets[i].Kind < assets[j].Kind
+			}
+
+			return assets[i].URL < assets[j].URL
+		})
+	}
+}
+
+// classify finds out the class of the error
+func classify(statusCode int, err error) string {
+	if statusCode >= 400 {
+		return ClassHTTP
+	}
+
+	if err == nil {
+		return ClassUnknown
+	}
+
+	// Get to the actual error
+	if urlError, ok := err.(*url.Error); ok {
+		err = urlError.Err
+	}
+
+	if netError, ok := err.(net.Error); ok && netError.Timeout() {
+		return ClassTimeout
+	}
+
+	if opError, ok := err.(*net.OpError); ok {
+		err = opError.Err
+	}
+
+	switch err.(type) {
+	case *net.DNSError:
+		return ClassDNS
+	case x509.UnknownAuthorityError,
+		x509.HostnameError,
+		x509.CertificateInvalidError,
+		tls.RecordHeaderError:
+		return ClassTLS
+	}
+
+	message := err.Error()
+
+	if strings.Contains(message, "x509:") || strings.Contains(message, "tls:") {
+		return ClassTLS
+	}
+
+	if strings.Contains(message, "connection") {
+		return ClassConnection
+	}
+
+	return ClassUnknown
+}
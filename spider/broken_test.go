@@ -0,0 +1,79 @@
+package spider_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/spider"
+)
+
+var _ = Describe("broken", func() {
+	var ts *httptest.Server
+
+	BeforeEach(func() {
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				io.WriteString(w, `<html><body><p><a href="/ok">ok</a> <a href="/nope">Not
+					there</a></p><a id="error" href="/error">error</a></body></html>`)
+			case "/ok":
+				io.WriteString(w, `<html><a href="http://127.0.0.1:1/refused">refused</a></html>`)
+			case "/error":
+				w.WriteHeader(500)
+			default:
+				w.WriteHeader(404)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	It("Should record why links are broken", func() {
+		crawler := New(ts.URL, "127.0.0.1:1")
+
+		for range crawler.Crawl() {
+		}
+
+		result, _ := crawler.Get()
+
+		Expect(result.Broken).To(ConsistOf(
+			&Broken{
+				URL:        ts.URL + "/nope",
+				StatusCode: 404,
+				Class:      ClassHTTP,
+				Message:    "Not Found",
+				Text:       "Not there",
+				Selector:   "body > p:nth-child(1) > a:nth-child(2)",
+			},
+			&Broken{
+				URL:        ts.URL + "/error",
+				StatusCode: 500,
+				Class:      ClassHTTP,
+				Message:    "Internal Server Error",
+				Text:       "error",
+				Selector:   "a#error",
+			},
+		))
+
+		Expect(result.Children).To(HaveLen(1))
+
+		refused := result.Children[0].Broken
+
+		Expect(refused).To(HaveLen(1))
+		Expect(refused[0].URL).To(Equal("http://127.0.0.1:1/refused"))
+		Expect(refused[0].Class).To(Equal(ClassConnection))
+		Expect(refused[0].Text).To(Equal("refused"))
+	})
+
+	It("Should tell the reason of the broken link", func() {
+		Expect((&Broken{StatusCode: 404, Class: ClassHTTP}).Reason()).To(Equal("404"))
+		Expect((&Broken{Class: ClassDNS, Message: "no such host"}).Reason()).To(Equal("dns"))
+		Expect((&Broken{Message: "nope"}).Reason()).To(Equal("nope"))
+	})
+})
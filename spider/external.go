@@ -0,0 +1,55 @@
+package spider
+
+import "net/http"
+
+// checkExternal requests every link of the page to the other domains,
+// which wasn't requested before, and records the broken ones on the page
+func (spider *Spider) checkExternal(output *Result) {
+	if spider.external == nil {
+		return
+	}
+
+	known := map[string]bool{}
+
+	for _, anchor := range output.anchors {
+		link := normalize(anchor.URL)
+
+		if known[link] || isHTTP(link) == false || spider.isFollowed(link) {
+			continue
+		}
+
+		known[link] = true
+
+		if spider.isAllowed(link) == false {
+			spider.skip(output, anchor.URL, "robots.txt")
+			continue
+		}
+
+		spider.waitGroup.Add(1)
+		go func(link string, broken *Broken) {
+			defer spider.waitGroup.Done()
+
+			check := spider.probe(spider.external, link)
+
+			switch {
+			case check.err != nil:
+				broken.Class = classify(0, check.err)
+				broken.Message = check.err.Error()
+			case check.statusCode >= 400:
+				broken.StatusCode = check.statusCode
+				broken.Class = ClassHTTP
+				broken.Message = http.StatusText(check.statusCode)
+			default:
+				return
+			}
+
+			spider.mutex.Lock()
+			output.Broken = append(output.Broken, broken)
+			spider.mutex.Unlock()
+		}(link, &Broken{
+			URL:      anchor.URL,
+			Text:     anchor.Text,
+			Selector: anchor.Selector,
+		})
+	}
+}
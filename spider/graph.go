@@ -0,0 +1,83 @@
+package spider
+
+import (
+	"sort"
+
+	"github.com/markelog/map/collect"
+)
+
+// Graph of the links between the pages, unlike the tree
+// it keeps every link, not only the first one which lead to the page
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+}
+
+// Node is the crawled page
+type Node struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+// Edge is the link from one page to another
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Text   string `json:"text,omitempty"`
+	Rel    string `json:"rel,omitempty"`
+}
+
+// Adjacency returns edges of the graph grouped by their source
+func (graph *Graph) Adjacency() map[string][]*Edge {
+	result := map[string][]*Edge{}
+
+	for _, edge := range graph.Edges {
+		result[edge.Source] = append(result[edge.Source], edge)
+	}
+
+	return result
+}
+
+// connect adds the page and its links to the graph
+func (spider *Spider) connect(source, name string, anchors []*collect.Anchor) {
+	if spider.graph == nil {
+		return
+	}
+
+	spider.mutex.Lock()
+	defer spider.mutex.Unlock()
+
+	spider.graph.Nodes = append(spider.graph.Nodes, &Node{
+		URL:  source,
+		Name: name,
+	})
+
+	for _, anchor := range anchors {
+		spider.graph.Edges = append(spider.graph.Edges, &Edge{
+			Source: source,
+			Target: anchor.URL,
+			Text:   anchor.Text,
+			Rel:    anchor.Rel,
+		})
+	}
+}
+
+// attachGraph puts the graph to the root, supposed to be called when crawl is finished
+func (spider *Spider) attachGraph() {
+	if spider.graph == nil || spider.Result == nil {
+		return
+	}
+
+	graph := spider.graph
+
+	// Crawl order is random, so make it stable
+	sort.SliceStable(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].URL < graph.Nodes[j].URL
+	})
+
+	sort.SliceStable(graph.Edges, func(i, j int) bool {
+		return graph.Edges[i].Source < graph.Edges[j].Source
+	})
+
+	spider.Result.Graph = graph
+}
@@ -0,0 +1,92 @@
+package spider_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/spider"
+)
+
+var _ = Describe("graph", func() {
+	var ts *httptest.Server
+
+	BeforeEach(func() {
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				io.WriteString(w, `<html><title>root</title><a href="/a">a</a><a href="/b">b</a></html>`)
+			case "/a":
+				io.WriteString(w, `<html><title>a</title><a href="/b" rel="nofollow">to b</a></html>`)
+			case "/b":
+				io.WriteString(w, `<html><title>b</title><a href="/">home</a></html>`)
+			default:
+				w.WriteHeader(404)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	It("Should collect all the links between the pages", func() {
+		crawler := NewWithOptions(ts.URL, WithGraph(true))
+
+		for range crawler.Crawl() {
+		}
+
+		result, _ := crawler.Get()
+
+		// "/" is the same page as the root, so it's not requested again
+		Expect(result.Graph.Nodes).To(Equal([]*Node{
+			{URL: ts.URL, Name: "root"},
+			{URL: ts.URL + "/a", Name: "a"},
+			{URL: ts.URL + "/b", Name: "b"},
+		}))
+
+		adjacency := result.Graph.Adjacency()
+
+		Expect(adjacency[ts.URL]).To(Equal([]*Edge{
+			{Source: ts.URL, Target: ts.URL + "/a", Text: "a"},
+			{Source: ts.URL, Target: ts.URL + "/b", Text: "b"},
+		}))
+		Expect(adjacency[ts.URL+"/a"]).To(Equal([]*Edge{
+			{Source: ts.URL + "/a", Target: ts.URL + "/b", Text: "to b", Rel: "nofollow"},
+		}))
+		Expect(adjacency[ts.URL+"/b"]).To(Equal([]*Edge{
+			{Source: ts.URL + "/b", Target: ts.URL + "/", Text: "home"},
+		}))
+	})
+
+	It("Should keep links of the pages with the same content", func() {
+		ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, `<html><title>same</title><a href="/a">a</a></html>`)
+		})
+
+		crawler := NewWithOptions(ts.URL, WithGraph(true))
+
+		for range crawler.Crawl() {
+		}
+
+		result, _ := crawler.Get()
+
+		Expect(result.Children).To(BeNil())
+		Expect(result.Graph.Nodes).To(HaveLen(2))
+		Expect(result.Graph.Edges).To(HaveLen(2))
+	})
+
+	It("Should not collect graph by default", func() {
+		crawler := New(ts.URL, "")
+
+		for range crawler.Crawl() {
+		}
+
+		result, _ := crawler.Get()
+
+		Expect(result.Graph).To(BeNil())
+	})
+})
@@ -0,0 +1,170 @@
+package spider
+
+import (
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly"
+)
+
+// limiter keeps the requests to one domain polite, like colly "LimitRule" does,
+// but waiting for it stops as soon as crawl is cancelled
+type limiter struct {
+	slots       chan bool
+	delay       time.Duration
+	randomDelay time.Duration
+}
+
+// limiter returns the limiter of the link domain, nil if the domain is not limited
+func (spider *Spider) limiter(link string) *limiter {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return nil
+	}
+
+	spider.mutex.Lock()
+	defer spider.mutex.Unlock()
+
+	if limit, ok := spider.limiters[parsed.Host]; ok {
+		return limit
+	}
+
+	delay := spider.delay
+
+	// "Crawl-delay" of robots.txt wins if it is longer
+	if spider.robots != nil && spider.robots.Delay(parsed.Host) > delay {
+		delay = spider.robots.Delay(parsed.Host)
+	}
+
+	var limit *limiter
+
+	// Delay means one request at the time, unless parallelism is set
+	if spider.domainParallelism > 0 || delay > 0 || spider.randomDelay > 0 {
+		parallelism := spider.domainParallelism
+		if parallelism < 1 {
+			parallelism = 1
+		}
+
+		limit = &limiter{
+			slots:       make(chan bool, parallelism),
+			delay:       delay,
+			randomDelay: spider.randomDelay,
+		}
+	}
+
+	spider.limiters[parsed.Host] = limit
+
+	return limit
+}
+
+// visit requests the page once there is a free slot for it,
+// ok is false if crawl was cancelled while waiting
+func (spider *Spider) visit(link string, context *colly.Context) (ok bool, err error) {
+	done, ok := spider.schedule(link)
+	if ok == false {
+		return false, nil
+	}
+	defer done()
+
+	// Walker frees the slots as soon as the response is there
+	context.Put("done", done)
+
+	return true, spider.collector.Request("GET", link, nil, context, nil)
+}
+
+// schedule waits for the free request slot of the crawl and of the link domain,
+// ok is false if crawl was cancelled while waiting, otherwise done has to be
+// called when the response is there, calling it more than once is fine
+func (spider *Spider) schedule(link string) (done func(), ok bool) {
+	if spider.occupy(spider.slots) == false {
+		return nil, false
+	}
+
+	limit := spider.limiter(link)
+	if limit != nil && spider.occupy(limit.slots) == false {
+		spider.vacate()
+		return nil, false
+	}
+
+	// Crawl might be cancelled while we were waiting for the slots
+	if spider.isCancelled() {
+		spider.vacate()
+		if limit != nil {
+			<-limit.slots
+		}
+
+		return nil, false
+	}
+
+	once := &sync.Once{}
+
+	return func() {
+		once.Do(func() {
+			spider.vacate()
+
+			if limit != nil {
+				spider.waitGroup.Add(1)
+				go spider.pause(limit)
+			}
+		})
+	}, true
+}
+
+// finish frees the slots of the request
+func finish(context *colly.Context) {
+	if done, ok := context.GetAny("done").(func()); ok {
+		done()
+	}
+}
+
+// occupy waits for the free slot, returns false if crawl was cancelled
+func (spider *Spider) occupy(slots chan bool) bool {
+	if slots == nil {
+		return true
+	}
+
+	select {
+	case slots <- true:
+		return true
+	case <-spider.cancelled():
+		return false
+	}
+}
+
+// vacate frees the request slot of the crawl
+func (spider *Spider) vacate() {
+	if spider.slots != nil {
+		<-spider.slots
+	}
+}
+
+// pause keeps the domain slot for the delay after the request
+func (spider *Spider) pause(limit *limiter) {
+	defer spider.waitGroup.Done()
+
+	delay := limit.delay
+	if limit.randomDelay > 0 {
+		delay += time.Duration(rand.Int63n(int64(limit.randomDelay)))
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-spider.cancelled():
+	}
+
+	<-limit.slots
+}
+
+// cancelled returns the channel which is closed when crawl is cancelled
+func (spider *Spider) cancelled() <-chan struct{} {
+	if spider.context == nil {
+		return nil
+	}
+
+	return spider.context.Done()
+}
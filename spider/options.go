@@ -0,0 +1,190 @@
+package spider
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures the Spider
+type Option func(*Spider)
+
+// WithDomains allows to follow the domains as addition to the root one
+func WithDomains(domains ...string) Option {
+	return func(spider *Spider) {
+		for _, domain := range domains {
+			if len(domain) == 0 {
+				continue
+			}
+
+			spider.collector.AllowedDomains = append(
+				spider.collector.AllowedDomains,
+				domain,
+			)
+		}
+	}
+}
+
+// WithUserAgent sets "User-Agent" header of every request
+func WithUserAgent(agent string) Option {
+	return func(spider *Spider) {
+		spider.collector.UserAgent = agent
+	}
+}
+
+// WithTimeout sets timeout of every request
+func WithTimeout(timeout time.Duration) Option {
+	return func(spider *Spider) {
+		spider.timeout = timeout
+		spider.collector.SetRequestTimeout(timeout)
+	}
+}
+
+// WithConcurrency limits how many requests might be made at the same time,
+// 0 means no limit
+func WithConcurrency(concurrency int) Option {
+	return func(spider *Spider) {
+		if concurrency <= 0 {
+			spider.slots = nil
+			return
+		}
+
+		spider.slots = make(chan bool, concurrency)
+	}
+}
+
+// WithDomainConcurrency limits how many requests might be made
+// to the same domain at the same time, 0 means no limit
+// unless delay is set, then it is 1
+func WithDomainConcurrency(concurrency int) Option {
+	return func(spider *Spider) {
+		spider.domainParallelism = concurrency
+	}
+}
+
+// WithDelay sets the pause between requests to the same domain
+func WithDelay(delay time.Duration) Option {
+	return func(spider *Spider) {
+		spider.delay = delay
+	}
+}
+
+// WithRandomDelay adds random pause, up to the provided one,
+// between requests to the same domain
+func WithRandomDelay(delay time.Duration) Option {
+	return func(spider *Spider) {
+		spider.randomDelay = delay
+	}
+}
+
+// WithHeaders adds headers to every request
+func WithHeaders(headers http.Header) Option {
+	return func(spider *Spider) {
+		if spider.headers == nil {
+			spider.headers = http.Header{}
+		}
+
+		for name, values := range headers {
+			for _, value := range values {
+				spider.headers.Add(name, value)
+			}
+		}
+	}
+}
+
+// WithTransport sets the transport which makes the requests
+func WithTransport(transport http.RoundTripper) Option {
+	return func(spider *Spider) {
+		spider.transport = transport
+	}
+}
+
+// WithIgnoreRobots makes spider to ignore robots.txt rules
+func WithIgnoreRobots(ignore bool) Option {
+	return func(spider *Spider) {
+		spider.ignoreRobots = ignore
+	}
+}
+
+// WithSitemap seeds the crawl with locations of the sitemaps,
+// if none provided they are discovered through robots.txt
+// or /sitemap.xml of the root
+func WithSitemap(addresses ...string) Option {
+	return func(spider *Spider) {
+		spider.useSitemap = true
+		spider.sitemaps = append(spider.sitemaps, addresses...)
+	}
+}
+
+// WithResponseHeaders sets which response headers
+// are kept in the page response, instead of the DefaultHeaders
+func WithResponseHeaders(names ...string) Option {
+	return func(spider *Spider) {
+		spider.responseHeaders = names
+	}
+}
+
+// WithGraph makes spider to collect the graph of all the links
+func WithGraph(graph bool) Option {
+	return func(spider *Spider) {
+		if graph {
+			spider.graph = &Graph{}
+			return
+		}
+
+		spider.graph = nil
+	}
+}
+
+// WithCheckAssets makes spider to request every asset once
+// and record the broken ones on the pages which refer to them
+func WithCheckAssets(check bool) Option {
+	return func(spider *Spider) {
+		if check {
+			spider.assets = make(map[string]*assetCheck)
+			return
+		}
+
+		spider.assets = nil
+	}
+}
+
+// WithCheckExternal makes spider to request every link to the other domains
+// once, without following it, and record the broken ones on the pages
+func WithCheckExternal(check bool) Option {
+	return func(spider *Spider) {
+		if check {
+			spider.external = make(map[string]*assetCheck)
+			return
+		}
+
+		spider.external = nil
+	}
+}
+
+// WithStylesheets makes spider to request the stylesheets of the pages
+// and add their url() and @import references to the assets, it's on by default,
+// like the pages, only the stylesheets of the allowed domains are requested
+func WithStylesheets(request bool) Option {
+	return func(spider *Spider) {
+		if request {
+			spider.stylesheets = make(map[string]*stylesheet)
+			return
+		}
+
+		spider.stylesheets = nil
+	}
+}
+
+// WithMaxDepth limits how deep spider goes from the root, 0 means no limit
+func WithMaxDepth(depth int) Option {
+	return func(spider *Spider) {
+		spider.MaxDepth = depth
+	}
+}
+
+// WithMaxPages limits amount of the requested pages, 0 means no limit
+func WithMaxPages(pages int) Option {
+	return func(spider *Spider) {
+		spider.MaxPages = pages
+	}
+}
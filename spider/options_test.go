@@ -0,0 +1,199 @@
+package spider_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/spider"
+)
+
+// countingTransport counts the requests passed through it
+type countingTransport struct {
+	mutex sync.Mutex
+	count int
+}
+
+func (transport *countingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	transport.mutex.Lock()
+	transport.count++
+	transport.mutex.Unlock()
+
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+var _ = Describe("options", func() {
+	var (
+		ts       *httptest.Server
+		requests []*http.Request
+		mutex    sync.Mutex
+		delay    time.Duration
+		body     string
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		delay = 0
+		body = `<html><title>test</title></html>`
+
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/robots.txt" {
+				w.WriteHeader(404)
+				return
+			}
+
+			mutex.Lock()
+			requests = append(requests, r)
+			mutex.Unlock()
+
+			time.Sleep(delay)
+
+			w.WriteHeader(200)
+			io.WriteString(w, body)
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	crawl := func(crawler *Spider) (errors []error) {
+		for value := range crawler.Crawl() {
+			if value.Error != nil {
+				errors = append(errors, value.Error)
+			}
+		}
+
+		return
+	}
+
+	It("Should keep New working", func() {
+		crawler := New(ts.URL, "example.com,example.net")
+
+		Expect(crawl(crawler)).To(BeEmpty())
+
+		result, _ := crawler.Get()
+		Expect(result.Name).To(Equal("test"))
+	})
+
+	It("Should set user agent", func() {
+		crawl(NewWithOptions(ts.URL, WithUserAgent("map-test")))
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].UserAgent()).To(Equal("map-test"))
+	})
+
+	It("Should set headers", func() {
+		headers := http.Header{}
+		headers.Set("X-Test", "yes")
+
+		crawl(NewWithOptions(ts.URL, WithHeaders(headers)))
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Header.Get("X-Test")).To(Equal("yes"))
+	})
+
+	It("Should use provided transport", func() {
+		transport := &countingTransport{}
+
+		crawl(NewWithOptions(ts.URL, WithTransport(transport)))
+
+		// robots.txt and the page
+		Expect(transport.count).To(Equal(2))
+	})
+
+	It("Should fail on timeout", func() {
+		delay = 100 * time.Millisecond
+
+		errors := crawl(NewWithOptions(ts.URL, WithTimeout(10*time.Millisecond)))
+
+		Expect(errors).To(HaveLen(1))
+	})
+
+	It("Should follow additional domains", func() {
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, `<html><title>other</title></html>`)
+		}))
+		defer other.Close()
+
+		otherURL, _ := url.Parse(other.URL)
+		body = `<html><title>test</title><a href="` + other.URL + `">other</a></html>`
+
+		crawler := NewWithOptions(ts.URL, WithDomains(otherURL.Host))
+		crawl(crawler)
+
+		result, _ := crawler.Get()
+		Expect(result.Children).To(HaveLen(1))
+		Expect(result.Children[0].Name).To(Equal("other"))
+	})
+
+	It("Should limit concurrency per domain", func() {
+		var (
+			active  = 0
+			maximum = 0
+			total   = 0
+		)
+
+		ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/robots.txt" {
+				w.WriteHeader(404)
+				return
+			}
+
+			mutex.Lock()
+			total++
+			active++
+			if active > maximum {
+				maximum = active
+			}
+			mutex.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mutex.Lock()
+			active--
+			mutex.Unlock()
+
+			fmt.Fprintf(w, `<html><a href="%s1">1</a><a href="%s2">2</a></html>`, r.URL.Path, r.URL.Path)
+		})
+
+		crawl(NewWithOptions(ts.URL, WithDomainConcurrency(1), WithMaxPages(7)))
+
+		Expect(total).To(Equal(7))
+		Expect(maximum).To(Equal(1))
+	})
+
+	It("Should pause between requests", func() {
+		body = `<html><title>test</title><a href="/1">1</a><a href="/2">2</a></html>`
+
+		start := time.Now()
+		crawl(NewWithOptions(ts.URL, WithDelay(50*time.Millisecond)))
+
+		Expect(requests).To(HaveLen(3))
+		Expect(time.Since(start)).To(BeNumerically(">=", 150*time.Millisecond))
+	})
+
+	It("Should ignore robots.txt", func() {
+		transport := &countingTransport{}
+
+		crawl(NewWithOptions(ts.URL, WithTransport(transport), WithIgnoreRobots(true)))
+
+		Expect(transport.count).To(Equal(1))
+	})
+
+	It("Should crawl with limited concurrency", func() {
+		body = `<html><title>test</title><a href="/1">1</a><a href="/2">2</a><a href="/3">3</a></html>`
+
+		crawler := NewWithOptions(ts.URL, WithConcurrency(1))
+		crawl(crawler)
+
+		Expect(requests).To(HaveLen(4))
+	})
+})
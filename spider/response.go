@@ -0,0 +1,160 @@
+package spider
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gocolly/colly"
+)
+
+// DefaultHeaders are response headers which are kept by default
+var DefaultHeaders = []string{
+	"Cache-Control",
+	"Content-Language",
+	"ETag",
+	"Server",
+	"X-Robots-Tag",
+}
+
+// maxRedirects is how many redirects we follow, same as colly does
+const maxRedirects = 10
+
+// Response metadata of the page
+type Response struct {
+	StatusCode int `json:"statusCode"`
+
+	// Redirects the request went through, without the requested url
+	Redirects []string `json:"redirects,omitempty"`
+
+	// URL of the page after redirects
+	URL string `json:"url"`
+
+	ContentType   string `json:"contentType"`
+	ContentLength int64  `json:"contentLength"`
+	LastModified  string `json:"lastModified,omitempty"`
+
+	// Time of the response in milliseconds
+	Time int64 `json:"time"`
+
+	// Headers which were selected to be kept
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// setTiming remembers when request started and what was requested,
+// since redirects are tracked by the requested url
+func (spider *Spider) setTiming() {
+	spider.collector.OnRequest(func(request *colly.Request) {
+		request.Ctx.Put("url", request.URL.String())
+		request.Ctx.Put("start", time.Now())
+	})
+}
+
+// redirectTransport remembers where every redirect leads to, colly only
+// tells the final url of the response and the redirects are followed as it does
+type redirectTransport struct {
+	spider *Spider
+}
+
+// RoundTrip makes the request with the transport of the spider
+func (transport *redirectTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	spider := transport.spider
+
+	next := spider.transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	response, err := next.RoundTrip(request)
+	if err != nil || isRedirect(response.StatusCode) == false {
+		return response, err
+	}
+
+	location, err := response.Location()
+	if err != nil {
+		return response, nil
+	}
+
+	spider.mutex.Lock()
+	spider.redirects[request.URL.String()] = location.String()
+	spider.mutex.Unlock()
+
+	return response, nil
+}
+
+// setRedirects tracks the redirects through the transport of the collector
+func (spider *Spider) setRedirects() {
+	spider.collector.WithTransport(&redirectTransport{spider: spider})
+}
+
+// redirectsOf follows the recorded redirects from the requested url
+// to the final one, the limit keeps us out of the loops
+func (spider *Spider) redirectsOf(requested, final string) (redirects []string) {
+	spider.mutex.Lock()
+	defer spider.mutex.Unlock()
+
+	for link := requested; link != final && len(redirects) < maxRedirects; {
+		next, ok := spider.redirects[link]
+		if ok == false {
+			break
+		}
+
+		redirects = append(redirects, next)
+		link = next
+	}
+
+	return
+}
+
+// isRedirect checks if the status code is the one http client follows
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+
+	return false
+}
+
+// respond collects metadata of the response
+func (spider *Spider) respond(response *colly.Response) *Response {
+	var (
+		headers   = *response.Headers
+		requested = response.Ctx.Get("url")
+		result    = &Response{
+			StatusCode:    response.StatusCode,
+			URL:           response.Request.URL.String(),
+			ContentType:   headers.Get("Content-Type"),
+			ContentLength: int64(len(response.Body)),
+			LastModified:  headers.Get("Last-Modified"),
+		}
+	)
+
+	length, err := strconv.ParseInt(headers.Get("Content-Length"), 10, 64)
+	if err == nil {
+		result.ContentLength = length
+	}
+
+	start, ok := response.Ctx.GetAny("start").(time.Time)
+	if ok {
+		result.Time = int64(time.Since(start) / time.Millisecond)
+	}
+
+	result.Redirects = spider.redirectsOf(requested, result.URL)
+
+	for _, name := range spider.responseHeaders {
+		value := headers.Get(name)
+		if len(value) == 0 {
+			continue
+		}
+
+		if result.Headers == nil {
+			result.Headers = map[string]string{}
+		}
+
+		result.Headers[http.CanonicalHeaderKey(name)] = value
+	}
+
+	return result
+}
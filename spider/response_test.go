@@ -0,0 +1,84 @@
+package spider_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/markelog/map/spider"
+)
+
+var _ = Describe("response", func() {
+	var ts *httptest.Server
+
+	BeforeEach(func() {
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				io.WriteString(w, `<html><a href="/old">old</a></html>`)
+			case "/old":
+				http.Redirect(w, r, "/older", 301)
+			case "/older":
+				http.Redirect(w, r, "/new", 302)
+			case "/new":
+				w.Header().Set("Content-Type", "text/html")
+				w.Header().Set("Last-Modified", "Tue, 02 Jan 2018 03:04:05 GMT")
+				w.Header().Set("Cache-Control", "no-cache")
+				w.Header().Set("X-Custom", "custom")
+				io.WriteString(w, `<html><title>new</title></html>`)
+			default:
+				w.WriteHeader(404)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	crawl := func(crawler *Spider) *Result {
+		for range crawler.Crawl() {
+		}
+
+		result, _ := crawler.Get()
+
+		return result
+	}
+
+	It("Should record response metadata", func() {
+		result := crawl(New(ts.URL, ""))
+
+		Expect(result.Response.StatusCode).To(Equal(200))
+		Expect(result.Response.URL).To(Equal(ts.URL))
+		Expect(result.Response.Redirects).To(BeNil())
+		Expect(result.Response.ContentLength).To(Equal(int64(35)))
+		Expect(result.Response.Time).To(BeNumerically(">=", 0))
+	})
+
+	It("Should record redirects and selected headers", func() {
+		result := crawl(New(ts.URL, ""))
+
+		Expect(result.Children).To(HaveLen(1))
+
+		response := result.Children[0].Response
+
+		Expect(response.Redirects).To(Equal([]string{ts.URL + "/older", ts.URL + "/new"}))
+		Expect(response.URL).To(Equal(ts.URL + "/new"))
+		Expect(response.ContentType).To(Equal("text/html"))
+		Expect(response.LastModified).To(Equal("Tue, 02 Jan 2018 03:04:05 GMT"))
+		Expect(response.Headers).To(Equal(map[string]string{
+			"Cache-Control": "no-cache",
+		}))
+	})
+
+	It("Should keep headers which were asked for", func() {
+		result := crawl(NewWithOptions(ts.URL, WithResponseHeaders("x-custom")))
+
+		Expect(result.Children[0].Response.Headers).To(Equal(map[string]string{
+			"X-Custom": "custom",
+		}))
+	})
+})
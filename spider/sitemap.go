@@ -0,0 +1,160 @@
+package spider
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/markelog/map/sitemap"
+)
+
+// Coverage compares the sitemap with the links of the site
+type Coverage struct {
+	// Sitemaps which were fetched
+	Sitemaps []string `json:"sitemaps"`
+
+	// Unlinked pages are in the sitemap, but no page links to them
+	Unlinked []string `json:"unlinked"`
+
+	// Unlisted pages are reachable by links, but not in the sitemap
+	Unlisted []string `json:"unlisted"`
+
+	// Errors of the sitemaps which couldn't be fetched
+	Errors []string `json:"errors,omitempty"`
+}
+
+// seed requests all the locations of the sitemaps
+func (spider *Spider) seed() {
+	if spider.useSitemap == false || spider.Result == nil {
+		return
+	}
+
+	var (
+		coverage  = &Coverage{}
+		seeds     = []string{}
+		addresses = spider.sitemaps
+		fetcher   = sitemap.New(spider.collector.UserAgent, spider.client())
+	)
+
+	if len(addresses) == 0 {
+		addresses = spider.discoverSitemaps()
+	}
+
+	fetcher.SetHeaders(spider.headers)
+
+	for _, address := range addresses {
+		if spider.isCancelled() {
+			break
+		}
+
+		locations, err := fetcher.Fetch(spider.context, address)
+
+		// Some of the nested sitemaps failed, but not the others
+		if nested, ok := err.(sitemap.Errors); ok {
+			for _, failure := range nested {
+				coverage.Errors = append(coverage.Errors, failure.Error())
+			}
+
+			err = nil
+		}
+
+		if err != nil {
+			coverage.Errors = append(coverage.Errors, err.Error())
+			continue
+		}
+
+		coverage.Sitemaps = append(coverage.Sitemaps, address)
+
+		spider.mutex.Lock()
+		for _, location := range locations {
+			spider.listed[normalize(location)] = true
+		}
+		spider.mutex.Unlock()
+
+		seeds = append(seeds, locations...)
+	}
+
+	spider.mutex.Lock()
+	spider.Result.Sitemap = coverage
+	spider.mutex.Unlock()
+
+	spider.request(spider.Result, seeds)
+}
+
+// discoverSitemaps finds sitemaps in robots.txt of the allowed domains,
+// falls back to the /sitemap.xml of the root
+func (spider *Spider) discoverSitemaps() (addresses []string) {
+	if spider.robots != nil {
+		for _, domain := range spider.collector.AllowedDomains {
+			addresses = append(addresses, spider.robots.Sitemaps(domain)...)
+		}
+	}
+
+	if len(addresses) > 0 {
+		return
+	}
+
+	data, _ := url.Parse(spider.path)
+
+	return []string{data.Scheme + "://" + data.Host + "/sitemap.xml"}
+}
+
+// track remembers the crawled page and where it links to
+func (spider *Spider) track(output *Result) {
+	if spider.useSitemap == false {
+		return
+	}
+
+	spider.mutex.Lock()
+	defer spider.mutex.Unlock()
+
+	// Root is reachable by definition
+	if output.parent == nil {
+		spider.linked[normalize(output.URL)] = true
+	}
+
+	spider.crawled = append(spider.crawled, normalize(output.URL))
+
+	for _, link := range output.Links {
+		spider.linked[normalize(link)] = true
+	}
+}
+
+// cover fills unlinked and unlisted pages of the coverage,
+// supposed to be called when crawl is finished
+func (spider *Spider) cover() {
+	if spider.Result == nil || spider.Result.Sitemap == nil {
+		return
+	}
+
+	coverage := spider.Result.Sitemap
+
+	for location := range spider.listed {
+		if spider.linked[location] == false {
+			coverage.Unlinked = append(coverage.Unlinked, location)
+		}
+	}
+
+	for _, page := range spider.crawled {
+		if spider.listed[page] == false {
+			coverage.Unlisted = append(coverage.Unlisted, page)
+		}
+	}
+
+	sort.Strings(coverage.Unlinked)
+	sort.Strings(coverage.Unlisted)
+}
+
+// normalize makes URLs comparable
+func normalize(link string) string {
+	data, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	data.Fragment = ""
+	if len(data.Path) == 0 {
+		data.Path = "/"
+	}
+
+	return data.String()
+}
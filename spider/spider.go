@@ -3,27 +3,63 @@
 package spider
 
 import (
+	"context"
+	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/go-errors/errors"
 	"github.com/gocolly/colly"
 
 	"github.com/markelog/map/collect"
 	"github.com/markelog/map/io"
 	"github.com/markelog/map/list"
+	"github.com/markelog/map/robots"
 	"github.com/markelog/map/validation"
 )
 
 // Result spider data that we eventually return
 type Result struct {
-	Assets   map[string][]string `json:"assets"`
-	URL      string              `json:"url"`
-	Name     string              `json:"name"`
-	Links    []string            `json:"links"`
-	Broken   []string            `json:"broken"`
-	Children []*Result           `json:"children"`
-	parent   *Result
+	Assets   map[string][]*collect.Asset `json:"assets"`
+	URL      string                      `json:"url"`
+	Name     string                      `json:"name"`
+	Links    []string                    `json:"links"`
+	Broken   []*Broken                   `json:"broken"`
+	Children []*Result                   `json:"children"`
+
+	// Response metadata of the page
+	Response *Response `json:"response,omitempty"`
+
+	// <link> elements of the page by their "rel", stylesheets are in the assets
+	Canonical  string               `json:"canonical,omitempty"`
+	Alternates []*collect.Alternate `json:"alternates,omitempty"`
+	Icons      []*collect.Icon      `json:"icons,omitempty"`
+	Manifest   string               `json:"manifest,omitempty"`
+	Preloads   []*collect.Preload   `json:"preloads,omitempty"`
+	Feeds      []*collect.Feed      `json:"feeds,omitempty"`
+
+	// Truncated marks the page which links were not followed
+	// because of the depth or pages limit
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Skipped links which were not requested on purpose
+	Skipped []*Skipped `json:"skipped,omitempty"`
+
+	// BrokenAssets of the page, only present if assets are checked
+	BrokenAssets []*BrokenAsset `json:"brokenAssets,omitempty"`
+
+	// Sitemap coverage, only present on the root
+	Sitemap *Coverage `json:"sitemap,omitempty"`
+
+	// Graph of all the links, only present on the root
+	Graph *Graph `json:"graph,omitempty"`
+
+	parent  *Result
+	anchors []*collect.Anchor
 }
 
 // Progress intermediate data
@@ -32,66 +68,240 @@ type Progress struct {
 	Error error
 }
 
+// Skipped link with the reason why it wasn't requested
+type Skipped struct {
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+// Parent of the node, nil for the root
+func (result *Result) Parent() *Result {
+	return result.parent
+}
+
+// RestoreParents restores the references to the parents in the whole tree,
+// they are lost when the result is serialized
+func (result *Result) RestoreParents() {
+	for _, child := range result.Children {
+		child.parent = result
+		child.RestoreParents()
+	}
+}
+
+// Page is the node of the flattened tree, parent and depth are known
+// from the walk, so they are there for the trees which were serialized as well
+type Page struct {
+	*Result
+
+	Parent string
+	Depth  int
+}
+
+// Flatten the tree in the order of the walk
+func Flatten(data *Result) (pages []*Page) {
+	if data == nil {
+		return
+	}
+
+	var walk func(node *Result, parent string, depth int)
+	walk = func(node *Result, parent string, depth int) {
+		pages = append(pages, &Page{
+			Result: node,
+			Parent: parent,
+			Depth:  depth,
+		})
+
+		for _, child := range node.Children {
+			walk(child, node.URL, depth+1)
+		}
+	}
+	walk(data, "", 0)
+
+	return
+}
+
+// AssetsSummary summarizes the asset counts, like "2 images, 1 styles"
+func (result *Result) AssetsSummary() string {
+	names := []string{}
+	for name, list := range result.Assets {
+		if len(list) > 0 {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	summary := []string{}
+	for _, name := range names {
+		summary = append(summary, strconv.Itoa(len(result.Assets[name]))+" "+name)
+	}
+
+	return strings.Join(summary, ", ")
+}
+
+// Depth counts the levels between the root and this node
+func (result *Result) Depth() (depth int) {
+	for parent := result.parent; parent != nil; parent = parent.parent {
+		depth++
+	}
+
+	return
+}
+
 // Spider configuration
 type Spider struct {
 	Result *Result
 	Error  error
 
+	// MaxDepth limits how deep spider goes from the root, 0 means no limit
+	MaxDepth int
+
+	// MaxPages limits amount of the requested pages, 0 means no limit
+	MaxPages int
+
 	Progress chan *Progress
 	isDone   bool
 
 	waitGroup *sync.WaitGroup
 	mutex     *sync.Mutex
 	list      *list.List
+	pages     int
+	queued    map[string]bool
+
+	headers http.Header
+	slots   chan bool
+	context context.Context
+
+	domainParallelism int
+	delay             time.Duration
+	randomDelay       time.Duration
+	limiters          map[string]*limiter
+
+	transport    http.RoundTripper
+	timeout      time.Duration
+	ignoreRobots bool
+	robots       *robots.Robots
+
+	responseHeaders []string
+	redirects       map[string]string
+
+	graph *Graph
+
+	assets      map[string]*assetCheck
+	external    map[string]*assetCheck
+	stylesheets map[string]*stylesheet
+
+	useSitemap bool
+	sitemaps   []string
+	listed     map[string]bool
+	linked     map[string]bool
+	crawled    []string
 
 	path       string
 	collector  *colly.Collector
 	validation *validation.Validation
 }
 
+// defaultTimeout of the requests, same as colly has
+const defaultTimeout = 10 * time.Second
+
 // New returns new instance of Spider
 func New(path, domains string) *Spider {
-	var (
-		data, _        = url.Parse(path)
-		domain         = data.Host
-		allowedDomains = []string{domain}
-	)
-
-	if len(domains) > 0 {
-		allowedDomains = append(strings.Split(domains, ","), domain)
+	if len(domains) == 0 {
+		return NewWithOptions(path)
 	}
 
+	return NewWithOptions(path, WithDomains(strings.Split(domains, ",")...))
+}
+
+// NewWithOptions returns new instance of Spider configured with options
+func NewWithOptions(path string, options ...Option) *Spider {
+	data, _ := url.Parse(path)
+
 	collector := colly.NewCollector()
-	collector.AllowedDomains = allowedDomains
+	collector.AllowedDomains = []string{data.Host}
 
 	// Be explicit
 	collector.AllowURLRevisit = false
 
-	return &Spider{
+	spider := &Spider{
 		isDone:   false,
 		Progress: make(chan *Progress),
 
 		waitGroup: &sync.WaitGroup{},
 		mutex:     &sync.Mutex{},
 		list:      list.New(),
+		queued:    make(map[string]bool),
+		limiters:  make(map[string]*limiter),
+
+		listed: make(map[string]bool),
+		linked: make(map[string]bool),
+
+		timeout:         defaultTimeout,
+		responseHeaders: DefaultHeaders,
+		redirects:       make(map[string]string),
+		stylesheets:     make(map[string]*stylesheet),
 
 		path:       path,
 		collector:  collector,
 		validation: validation.New(path),
 	}
+
+	for _, option := range options {
+		option(spider)
+	}
+
+	return spider
 }
 
 // Crawl visits the sites and collects data from them
 func (spider *Spider) Crawl() (progress chan *Progress) {
+	return spider.CrawlContext(context.Background())
+}
+
+// CrawlContext visits the sites and collects data from them until
+// context is cancelled, then waits for the in-flight requests
+// and closes the progress channel
+func (spider *Spider) CrawlContext(ctx context.Context) (progress chan *Progress) {
+	spider.context = ctx
+
+	spider.setRobots()
+	spider.setHeaders()
+	spider.setTiming()
+	spider.setRedirects()
 	spider.setError()
 	spider.setWalker()
 
-	spider.collector.Visit(spider.path)
+	// Progress is not consumed until we return it, so don't block on it
+	spider.waitGroup.Add(1)
+	go func() {
+		defer spider.waitGroup.Done()
+
+		if spider.isAllowed(spider.path) == false {
+			err := errors.New(spider.path + " is disallowed by robots.txt")
+
+			spider.mutex.Lock()
+			spider.Error = err
+			spider.mutex.Unlock()
+
+			spider.emitData(&Progress{
+				Error: err,
+			})
+			return
+		}
+
+		spider.reserve(spider.path)
+		spider.visit(spider.path, colly.NewContext())
+		spider.seed()
+	}()
 
 	go func() {
 		spider.waitGroup.Wait()
 
 		spider.mutex.Lock()
+		spider.cover()
+		spider.attachGraph()
+		spider.sortBroken()
 		spider.isDone = true
 		close(spider.Progress)
 		spider.mutex.Unlock()
@@ -125,6 +335,72 @@ func (spider *Spider) emitData(data *Progress) {
 	}
 }
 
+// setRobots fetches robots.txt of every allowed domain
+func (spider *Spider) setRobots() {
+	if spider.ignoreRobots {
+		return
+	}
+
+	spider.robots = robots.New(spider.collector.UserAgent, spider.client())
+	spider.robots.SetHeaders(spider.headers)
+	spider.robots.SetContext(spider.context)
+
+	data, _ := url.Parse(spider.path)
+	for _, domain := range spider.collector.AllowedDomains {
+		// Absent robots.txt allows everything
+		spider.robots.Fetch(data.Scheme, domain)
+	}
+}
+
+// client returns http client for the requests made outside of the collector,
+// they have to be made with the context of the crawl
+func (spider *Spider) client() *http.Client {
+	return &http.Client{
+		Transport: spider.transport,
+		Timeout:   spider.timeout,
+	}
+}
+
+// isFollowed checks if the link leads to one of the allowed domains
+func (spider *Spider) isFollowed(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	for _, domain := range spider.collector.AllowedDomains {
+		if parsed.Host == domain {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAllowed checks link against robots.txt rules
+func (spider *Spider) isAllowed(link string) bool {
+	if spider.robots == nil {
+		return true
+	}
+
+	return spider.robots.Allowed(link)
+}
+
+// setHeaders sets additional headers for every request
+func (spider *Spider) setHeaders() {
+	if len(spider.headers) == 0 {
+		return
+	}
+
+	spider.collector.OnRequest(func(request *colly.Request) {
+		for name, values := range spider.headers {
+			for _, value := range values {
+				request.Headers.Add(name, value)
+			}
+		}
+	})
+}
+
 // setError sets error handler for the spider
 func (spider *Spider) setError() {
 	spider.collector.OnError(func(response *colly.Response, err error) {
@@ -133,6 +409,8 @@ func (spider *Spider) setError() {
 
 		// If first urls breaks
 		if spider.Result == nil {
+			spider.Error = newBroken(nil, response, err)
+
 			spider.waitGroup.Add(1)
 
 			go func() {
@@ -154,18 +432,22 @@ func (spider *Spider) setError() {
 			return
 		}
 
-		parent.Broken = append(parent.Broken, response.Request.URL.String())
+		parent.Broken = append(parent.Broken, newBroken(parent, response, err))
 	})
 }
 
 // setWalker sets crawler walker
 func (spider *Spider) setWalker() {
 	spider.collector.OnResponse(func(response *colly.Response) {
+		finish(response.Ctx)
+
 		body := response.Body
 
 		// Links might lead to the same page, which we might already
-		// tackled, so we have to check the response body instead
-		if spider.list.Has(body) {
+		// tackled, so we have to check the response body instead,
+		// though graph still needs links of every page
+		duplicate := spider.list.Has(body)
+		if duplicate && spider.graph == nil {
 			return
 		}
 		spider.list.Add(body)
@@ -182,13 +464,46 @@ func (spider *Spider) setWalker() {
 			return
 		}
 
-		collection := collect.New(doc)
+		var (
+			collection = collect.NewWithURL(doc, response.Request.URL.String())
+			anchors    = collection.Anchors(response.Request)
+			links      []string
+		)
+
+		spider.connect(response.Request.URL.String(), collection.Title(), anchors)
+		if duplicate {
+			return
+		}
+
+		for _, anchor := range anchors {
+			links = append(links, anchor.URL)
+		}
+
+		var (
+			relations = collection.Relations()
+			assets    = collection.Assets()
+		)
+
+		// Assets of the stylesheets have to be there before the page is emitted
+		spider.importStyles(collection, assets)
 
 		output := &Result{
-			Assets: collection.Assets(),
+			Assets: assets,
 			Name:   collection.Title(),
-			Links:  collection.Links(response.Request),
+			Links:  links,
 			URL:    response.Request.URL.String(),
+
+			Response: spider.respond(response),
+
+			Canonical:  relations.Canonical,
+			Alternates: relations.Alternates,
+			Icons:      relations.Icons,
+			Manifest:   relations.Manifest,
+			Preloads:   relations.Preloads,
+			Feeds:      relations.Feeds,
+
+			parent:  getParent(response),
+			anchors: anchors,
 		}
 
 		if spider.Result == nil {
@@ -203,26 +518,139 @@ func (spider *Spider) setWalker() {
 			spider.waitGroup.Done()
 		}()
 
+		spider.track(output)
+		spider.checkAssets(output)
+		spider.checkExternal(output)
 		spider.appendToParent(output, response)
 		spider.request(output, output.Links)
 	})
 }
 
 // request multiple links from provided arguments
-func (spider Spider) request(output *Result, links []string) {
-	spider.waitGroup.Add(len(links))
+func (spider *Spider) request(output *Result, links []string) {
+	if len(links) == 0 {
+		return
+	}
+
+	// Links of the deepest pages are kept, but not followed
+	if spider.MaxDepth > 0 && output.Depth() >= spider.MaxDepth {
+		spider.truncate(output)
+		return
+	}
 
 	for _, link := range links {
+		// Links to the other domains are never requested
+		if spider.isFollowed(link) == false {
+			continue
+		}
+
+		if spider.isAllowed(link) == false {
+			spider.skip(output, link, "robots.txt")
+			continue
+		}
+
+		if spider.isCancelled() {
+			spider.truncate(output)
+			return
+		}
+
+		fresh, ok := spider.reserve(link)
+		if ok == false {
+			spider.truncate(output)
+			return
+		}
+
+		// Already requested by this or the other page
+		if fresh == false {
+			continue
+		}
+
 		context := colly.NewContext()
 		context.Put("parent", output)
 
+		spider.waitGroup.Add(1)
 		go func(link string, context *colly.Context) {
-			spider.collector.Request("GET", link, nil, context, nil)
-			spider.waitGroup.Done()
+			defer spider.waitGroup.Done()
+
+			// Crawl might be cancelled while we were waiting for the slot
+			ok, err := spider.visit(link, context)
+			if ok == false {
+				spider.release()
+				spider.truncate(output)
+				return
+			}
+
+			// Nothing was fetched, so give the page back to the budget
+			if err == colly.ErrAlreadyVisited || err == colly.ErrForbiddenDomain {
+				spider.release()
+			}
 		}(link, context)
 	}
 }
 
+// isCancelled checks if crawl was cancelled
+func (spider *Spider) isCancelled() bool {
+	if spider.context == nil {
+		return false
+	}
+
+	return spider.context.Err() != nil
+}
+
+// reserve takes one page from the pages budget for the link, link which
+// was reserved before is not fresh and takes nothing, ok is false
+// if budget is exhausted
+func (spider *Spider) reserve(link string) (fresh, ok bool) {
+	spider.mutex.Lock()
+	defer spider.mutex.Unlock()
+
+	// "http://example.com" and "http://example.com/#top" are the same page
+	key := normalize(link)
+	if spider.queued[key] {
+		return false, true
+	}
+
+	if spider.MaxPages > 0 && spider.pages >= spider.MaxPages {
+		return false, false
+	}
+
+	spider.queued[key] = true
+	spider.pages++
+
+	return true, true
+}
+
+// release returns page to the pages budget
+func (spider *Spider) release() {
+	spider.mutex.Lock()
+	spider.pages--
+	spider.mutex.Unlock()
+}
+
+// truncate marks the node as the one where crawl was cut short
+func (spider *Spider) truncate(output *Result) {
+	spider.mutex.Lock()
+	output.Truncated = true
+	spider.mutex.Unlock()
+}
+
+// skip records the link which wasn't requested on purpose
+func (spider *Spider) skip(output *Result, link, reason string) {
+	spider.mutex.Lock()
+	defer spider.mutex.Unlock()
+
+	for _, skipped := range output.Skipped {
+		if skipped.URL == link {
+			return
+		}
+	}
+
+	output.Skipped = append(output.Skipped, &Skipped{
+		URL:    link,
+		Reason: reason,
+	})
+}
+
 // getParent gets parent from the context of the response
 func getParent(response *colly.Response) (parent *Result) {
 	parentInterface := response.Request.Ctx.GetAny("parent")
@@ -235,7 +663,7 @@ func getParent(response *colly.Response) (parent *Result) {
 }
 
 // appendToParent append node to their parent
-func (spider Spider) appendToParent(output *Result, response *colly.Response) {
+func (spider *Spider) appendToParent(output *Result, response *colly.Response) {
 	spider.mutex.Lock()
 	defer spider.mutex.Unlock()
 
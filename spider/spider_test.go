@@ -1,6 +1,8 @@
 package spider_test
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -8,11 +10,14 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/sanity-io/litter"
 
+	"github.com/markelog/map/collect"
 	. "github.com/markelog/map/spider"
 )
 
@@ -53,7 +58,7 @@ var _ = Describe("spider", func() {
 				data, _    = ioutil.ReadFile("testdata/data.txt")
 				json       = string(data)
 				urlData, _ = url.Parse(ts.URL)
-				expected   = strings.Replace(json, "$URL", urlData.Host, 1)
+				expected   = strings.Replace(json, "$URL", urlData.Host, -1)
 				progress   = spidy.Crawl()
 			)
 
@@ -63,11 +68,490 @@ var _ = Describe("spider", func() {
 				Expect(value.Error).To(BeNil())
 				Expect(value.Data).ToNot(BeNil())
 
+				// Timing is different every time
+				value.Data.Response.Time = 0
+
 				Expect(litter.Sdump(value.Data)).To(Equal(expected))
 			}
 		})
 	})
 
+	Describe("Limits", func() {
+		var site *httptest.Server
+
+		BeforeEach(func() {
+			// Every page links to the next one: / -> /1 -> /2 -> /3
+			site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var current int
+				fmt.Sscanf(r.URL.Path, "/%d", &current)
+
+				w.WriteHeader(200)
+				fmt.Fprintf(
+					w,
+					`<html><title>%d</title><a href="/%d">next</a></html>`,
+					current,
+					current+1,
+				)
+			}))
+		})
+
+		AfterEach(func() {
+			site.Close()
+		})
+
+		crawl := func(crawler *Spider) (pages int) {
+			for value := range crawler.Crawl() {
+				Expect(value.Error).To(BeNil())
+				pages++
+			}
+
+			return
+		}
+
+		It("Should not follow links deeper than the depth limit", func() {
+			crawler := New(site.URL, "")
+			crawler.MaxDepth = 2
+
+			Expect(crawl(crawler)).To(Equal(3))
+
+			result, _ := crawler.Get()
+			last := result.Children[0].Children[0]
+
+			Expect(last.Depth()).To(Equal(2))
+			Expect(last.Parent()).To(Equal(result.Children[0]))
+			Expect(result.Parent()).To(BeNil())
+			Expect(last.Truncated).To(Equal(true))
+			Expect(last.Links).To(Equal([]string{site.URL + "/3"}))
+			Expect(last.Children).To(BeNil())
+			Expect(result.Truncated).To(Equal(false))
+		})
+
+		It("Should not request more pages than the pages limit", func() {
+			crawler := New(site.URL, "")
+			crawler.MaxPages = 2
+
+			Expect(crawl(crawler)).To(Equal(2))
+
+			result, _ := crawler.Get()
+			last := result.Children[0]
+
+			Expect(last.Truncated).To(Equal(true))
+			Expect(last.Children).To(BeNil())
+		})
+
+		It("Should not spend the pages limit on the repeated links", func() {
+			repeated := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(200)
+
+				if r.URL.Path != "/" {
+					fmt.Fprintf(w, `<html><title>%s</title></html>`, r.URL.Path)
+					return
+				}
+
+				io.WriteString(w, `<html>
+					<a href="/">home</a><a href="/">home</a><a href="/">home</a>
+					<a href="/a">a</a><a href="/b">b</a>
+				</html>`)
+			}))
+			defer repeated.Close()
+
+			crawler := New(repeated.URL, "")
+			crawler.MaxPages = 3
+
+			Expect(crawl(crawler)).To(Equal(3))
+
+			result, _ := crawler.Get()
+
+			Expect(result.Truncated).To(Equal(false))
+			Expect(result.Children).To(HaveLen(2))
+		})
+	})
+
+	Describe("CrawlContext", func() {
+		It("Should stop the crawl when context is cancelled", func() {
+			// Endless chain of pages: / -> /1 -> /2 -> ...
+			site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var current int
+				fmt.Sscanf(r.URL.Path, "/%d", &current)
+
+				time.Sleep(10 * time.Millisecond)
+
+				w.WriteHeader(200)
+				fmt.Fprintf(w, `<html><a href="/%d">next</a></html>`, current+1)
+			}))
+			defer site.Close()
+
+			var (
+				ctx, cancel = context.WithCancel(context.Background())
+				crawler     = New(site.URL, "")
+				pages       = 0
+			)
+			defer cancel()
+
+			for value := range crawler.CrawlContext(ctx) {
+				Expect(value.Error).To(BeNil())
+
+				pages++
+				if pages == 2 {
+					cancel()
+				}
+			}
+
+			result, _ := crawler.Get()
+
+			last := result
+			for len(last.Children) > 0 {
+				last = last.Children[0]
+			}
+
+			Expect(pages).To(BeNumerically("<", 5))
+			Expect(last.Truncated).To(Equal(true))
+		})
+
+		It("Should not wait for robots.txt when context is cancelled", func() {
+			release := make(chan bool)
+
+			site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/robots.txt" {
+					<-release
+				}
+
+				w.WriteHeader(200)
+			}))
+			defer site.Close()
+			defer close(release)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			time.AfterFunc(100*time.Millisecond, cancel)
+
+			start := time.Now()
+			for range New(site.URL, "").CrawlContext(ctx) {
+			}
+
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+
+		It("Should not wait for the delay when context is cancelled", func() {
+			var (
+				mutex    sync.Mutex
+				requests = 0
+			)
+
+			site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				requests++
+				mutex.Unlock()
+
+				w.WriteHeader(200)
+
+				if r.URL.Path != "/" {
+					return
+				}
+
+				for i := 0; i < 50; i++ {
+					fmt.Fprintf(w, `<a href="/%d">%d</a>`, i, i)
+				}
+			}))
+			defer site.Close()
+
+			var (
+				ctx, cancel = context.WithCancel(context.Background())
+				crawler     = NewWithOptions(site.URL, WithDelay(100*time.Millisecond), WithIgnoreRobots(true))
+			)
+			defer cancel()
+
+			time.AfterFunc(300*time.Millisecond, cancel)
+
+			start := time.Now()
+			for range crawler.CrawlContext(ctx) {
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			Expect(requests).To(BeNumerically("<=", 4))
+		})
+	})
+
+	Describe("Robots", func() {
+		var site *httptest.Server
+
+		BeforeEach(func() {
+			site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/robots.txt" {
+					io.WriteString(w, "User-agent: *\nDisallow: /private\n")
+					return
+				}
+
+				fmt.Fprintf(
+					w,
+					`<html><title>%s</title><a href="/public">public</a><a href="/private">private</a></html>`,
+					r.URL.Path,
+				)
+			}))
+		})
+
+		AfterEach(func() {
+			site.Close()
+		})
+
+		It("Should skip disallowed links", func() {
+			crawler := New(site.URL, "")
+
+			for range crawler.Crawl() {
+			}
+
+			result, _ := crawler.Get()
+
+			Expect(result.Children).To(HaveLen(1))
+			Expect(result.Children[0].Name).To(Equal("/public"))
+			Expect(result.Skipped).To(Equal([]*Skipped{
+				{URL: site.URL + "/private", Reason: "robots.txt"},
+			}))
+		})
+
+		It("Should follow disallowed links if robots.txt is ignored", func() {
+			crawler := NewWithOptions(site.URL, WithIgnoreRobots(true))
+
+			for range crawler.Crawl() {
+			}
+
+			result, _ := crawler.Get()
+
+			Expect(result.Children).To(HaveLen(2))
+			Expect(result.Skipped).To(BeNil())
+		})
+	})
+
+	Describe("Sitemap", func() {
+		var site *httptest.Server
+
+		BeforeEach(func() {
+			site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/robots.txt":
+					fmt.Fprintf(w, "Sitemap: http://%s/sitemap.xml\n", r.Host)
+				case "/sitemap.xml":
+					fmt.Fprintf(
+						w,
+						`<urlset><url><loc>http://%s/</loc></url><url><loc>http://%s/orphan</loc></url></urlset>`,
+						r.Host,
+						r.Host,
+					)
+				case "/index.xml":
+					fmt.Fprintf(
+						w,
+						`<sitemapindex><sitemap><loc>http://%s/nope.xml</loc></sitemap><sitemap><loc>http://%s/sitemap.xml</loc></sitemap></sitemapindex>`,
+						r.Host,
+						r.Host,
+					)
+				case "/":
+					io.WriteString(w, `<html><title>root</title><a href="/linked">linked</a></html>`)
+				default:
+					fmt.Fprintf(w, `<html><title>%s</title></html>`, r.URL.Path)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			site.Close()
+		})
+
+		It("Should seed the crawl with the sitemap from robots.txt", func() {
+			crawler := NewWithOptions(site.URL, WithSitemap())
+
+			for range crawler.Crawl() {
+			}
+
+			result, _ := crawler.Get()
+
+			names := []string{}
+			for _, child := range result.Children {
+				names = append(names, child.Name)
+			}
+
+			Expect(names).To(ConsistOf("/linked", "/orphan"))
+			Expect(result.Sitemap).To(Equal(&Coverage{
+				Sitemaps: []string{site.URL + "/sitemap.xml"},
+				Unlinked: []string{site.URL + "/orphan"},
+				Unlisted: []string{site.URL + "/linked"},
+			}))
+		})
+
+		It("Should report sitemap which can't be fetched", func() {
+			crawler := NewWithOptions(site.URL, WithSitemap(site.URL+"/nope.xml"))
+
+			for range crawler.Crawl() {
+			}
+
+			result, _ := crawler.Get()
+
+			Expect(result.Children).To(HaveLen(1))
+			Expect(result.Sitemap.Errors).To(HaveLen(1))
+		})
+
+		It("Should keep the sitemaps of the index which didn't fail", func() {
+			crawler := NewWithOptions(site.URL, WithSitemap(site.URL+"/index.xml"))
+
+			for range crawler.Crawl() {
+			}
+
+			result, _ := crawler.Get()
+
+			Expect(result.Children).To(HaveLen(2))
+			Expect(result.Sitemap.Sitemaps).To(Equal([]string{site.URL + "/index.xml"}))
+			Expect(result.Sitemap.Unlinked).To(Equal([]string{site.URL + "/orphan"}))
+			Expect(result.Sitemap.Errors).To(HaveLen(1))
+			Expect(result.Sitemap.Errors[0]).To(ContainSubstring(site.URL + "/nope.xml"))
+		})
+
+		It("Should not wait for the sitemap when context is cancelled", func() {
+			release := make(chan bool)
+
+			slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/slow.xml" {
+					<-release
+				}
+
+				io.WriteString(w, `<html><title>root</title></html>`)
+			}))
+			defer slow.Close()
+			defer close(release)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			time.AfterFunc(100*time.Millisecond, cancel)
+
+			start := time.Now()
+			crawler := NewWithOptions(slow.URL, WithSitemap(slow.URL+"/slow.xml"), WithIgnoreRobots(true))
+			for range crawler.CrawlContext(ctx) {
+			}
+
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+
+		It("Should not use sitemap by default", func() {
+			crawler := New(site.URL, "")
+
+			for range crawler.Crawl() {
+			}
+
+			result, _ := crawler.Get()
+
+			Expect(result.Children).To(HaveLen(1))
+			Expect(result.Sitemap).To(BeNil())
+		})
+	})
+
+	Describe("External", func() {
+		var (
+			site, external *httptest.Server
+			requests       map[string]int
+			mutex          sync.Mutex
+		)
+
+		BeforeEach(func() {
+			requests = map[string]int{}
+
+			external = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				requests[r.URL.Path]++
+				mutex.Unlock()
+
+				if r.URL.Path == "/missing" {
+					w.WriteHeader(404)
+				}
+			}))
+
+			site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(
+					w,
+					`<html><a href="%s/missing">missing</a><a href="%s/fine">fine</a><a href="%s/fine#top">fine</a></html>`,
+					external.URL,
+					external.URL,
+					external.URL,
+				)
+			}))
+		})
+
+		AfterEach(func() {
+			site.Close()
+			external.Close()
+		})
+
+		It("Should check the links to the other domains once", func() {
+			crawler := NewWithOptions(site.URL, WithCheckExternal(true))
+
+			for range crawler.Crawl() {
+			}
+
+			result, _ := crawler.Get()
+
+			Expect(result.Children).To(BeNil())
+			Expect(result.Broken).To(Equal([]*Broken{
+				{
+					URL:        external.URL + "/missing",
+					StatusCode: 404,
+					Class:      ClassHTTP,
+					Message:    "Not Found",
+					Text:       "missing",
+					Selector:   "body > a:nth-child(1)",
+				},
+			}))
+			Expect(requests).To(Equal(map[string]int{"/missing": 1, "/fine": 1}))
+		})
+
+		It("Should not request the links to the other domains by default", func() {
+			crawler := New(site.URL, "")
+
+			for range crawler.Crawl() {
+			}
+
+			result, _ := crawler.Get()
+
+			Expect(result.Broken).To(BeNil())
+			Expect(requests).To(BeEmpty())
+		})
+	})
+
+	Describe("Flatten", func() {
+		It("Should flatten the tree with the parents and depths", func() {
+			var (
+				b    = &Result{URL: "http://example.com/b"}
+				a    = &Result{URL: "http://example.com/a", Children: []*Result{b}}
+				c    = &Result{URL: "http://example.com/c"}
+				root = &Result{URL: "http://example.com", Children: []*Result{a, c}}
+			)
+
+			Expect(Flatten(root)).To(Equal([]*Page{
+				{Result: root, Parent: "", Depth: 0},
+				{Result: a, Parent: "http://example.com", Depth: 1},
+				{Result: b, Parent: "http://example.com/a", Depth: 2},
+				{Result: c, Parent: "http://example.com", Depth: 1},
+			}))
+		})
+
+		It("Should flatten nothing", func() {
+			Expect(Flatten(nil)).To(BeNil())
+		})
+	})
+
+	Describe("AssetsSummary", func() {
+		It("Should count the assets of every kind", func() {
+			result := &Result{Assets: map[string][]*collect.Asset{
+				"styles": {{URL: "a.css"}},
+				"images": {{URL: "a.png"}, {URL: "b.png"}},
+				"fonts":  nil,
+			}}
+
+			Expect(result.AssetsSummary()).To(Equal("2 images, 1 styles"))
+		})
+	})
+
 	Describe("Get", func() {
 		It("Should correct validate the input", func() {
 			result, err := spidy.Get()
@@ -75,5 +559,26 @@ var _ = Describe("spider", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(reflect.TypeOf(result).String()).To(Equal("*spider.Result"))
 		})
+
+		It("Should return the broken root as error", func() {
+			broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(503)
+			}))
+			defer broken.Close()
+
+			crawler := New(broken.URL, "")
+			for range crawler.Crawl() {
+			}
+
+			result, err := crawler.Get()
+
+			Expect(result).To(BeNil())
+			Expect(err).To(Equal(&Broken{
+				URL:        broken.URL,
+				StatusCode: 503,
+				Class:      ClassHTTP,
+				Message:    "Service Unavailable",
+			}))
+		})
 	})
 })
@@ -0,0 +1,109 @@
+package spider
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/markelog/map/collect"
+)
+
+// maxStylesheet limits how much of the stylesheet is read
+const maxStylesheet = 5 << 20
+
+// stylesheet is the cached result of the stylesheet request
+type stylesheet struct {
+	done       chan bool
+	references []*collect.Reference
+}
+
+// importStyles requests the stylesheets of the page, including the imported
+// ones, and adds their references to the assets, like pages, only the
+// stylesheets of the allowed domains, which robots.txt allows, are requested
+func (spider *Spider) importStyles(collection *collect.Collect, assets map[string][]*collect.Asset) {
+	if spider.stylesheets == nil {
+		return
+	}
+
+	var (
+		queue   = []string{}
+		visited = map[string]bool{}
+	)
+
+	for _, asset := range assets["styles"] {
+		queue = append(queue, asset.URL)
+	}
+
+	for len(queue) > 0 {
+		link := queue[0]
+		queue = queue[1:]
+
+		if visited[link] || isHTTP(link) == false {
+			continue
+		}
+		visited[link] = true
+
+		if spider.isFollowed(link) == false || spider.isAllowed(link) == false {
+			continue
+		}
+
+		for _, reference := range spider.stylesheet(link) {
+			asset := collection.ResolveFrom(link, reference.URL)
+			assets[reference.Kind] = append(assets[reference.Kind], asset)
+
+			if reference.Kind == "styles" {
+				queue = append(queue, asset.URL)
+			}
+		}
+	}
+}
+
+// stylesheet gets the url() and @import references of the stylesheet,
+// it's cached the same way as the result of the probe
+func (spider *Spider) stylesheet(link string) []*collect.Reference {
+	spider.mutex.Lock()
+	sheet, ok := spider.stylesheets[link]
+	if ok == false {
+		sheet = &stylesheet{done: make(chan bool)}
+		spider.stylesheets[link] = sheet
+	}
+	spider.mutex.Unlock()
+
+	if ok {
+		<-sheet.done
+		return sheet.references
+	}
+
+	defer close(sheet.done)
+
+	body, err := spider.requestStylesheet(link)
+	if err != nil {
+		return nil
+	}
+
+	sheet.references = collect.ParseCSS(string(body))
+
+	return sheet.references
+}
+
+// requestStylesheet gets the body of the stylesheet, nothing if it's not there,
+// once there is a free slot for it, like the pages do
+func (spider *Spider) requestStylesheet(link string) ([]byte, error) {
+	done, ok := spider.schedule(link)
+	if ok == false {
+		return nil, spider.context.Err()
+	}
+	defer done()
+
+	response, err := spider.fetch(http.MethodGet, link)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 400 {
+		return nil, nil
+	}
+
+	return ioutil.ReadAll(io.LimitReader(response.Body, maxStylesheet))
+}
@@ -0,0 +1,149 @@
+package spider_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/markelog/map/collect"
+	. "github.com/markelog/map/spider"
+)
+
+var _ = Describe("stylesheets", func() {
+	var (
+		ts, other *httptest.Server
+		requests  map[string]int
+		mutex     sync.Mutex
+	)
+
+	BeforeEach(func() {
+		requests = map[string]int{}
+
+		other = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requests["other"+r.URL.Path]++
+			mutex.Unlock()
+
+			w.Header().Set("Content-Type", "text/css")
+			io.WriteString(w, `@font-face { src: url(other.woff) }`)
+		}))
+
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requests[r.URL.Path]++
+			mutex.Unlock()
+
+			serve := func(contentType, body string) {
+				w.Header().Set("Content-Type", contentType)
+				w.WriteHeader(200)
+				io.WriteString(w, body)
+			}
+
+			switch r.URL.Path {
+			case "/robots.txt":
+				serve("text/plain", "User-agent: *\nDisallow: /private\n")
+			case "/":
+				serve("text/html", `<html>
+					<link rel="stylesheet" href="/css/main.css">
+					<link rel="stylesheet" href="/missing.css">
+					<link rel="stylesheet" href="/private/hidden.css">
+					<link rel="stylesheet" href="`+other.URL+`/other.css">
+					<a href="/a">a</a>
+				</html>`)
+			case "/a":
+				serve("text/html", `<html><link rel="stylesheet" href="/css/main.css"></html>`)
+			case "/css/main.css":
+				serve("text/css", `
+					@import "theme.css";
+					@font-face { src: url(../fonts/sans.woff2) }
+					body { background: url(/bg.png) }
+				`)
+			case "/css/theme.css":
+				serve("text/css", `@import "main.css"; .logo { background: url(logo.svg) }`)
+			default:
+				w.WriteHeader(404)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+		other.Close()
+	})
+
+	crawl := func(options ...Option) *Result {
+		crawler := NewWithOptions(ts.URL, options...)
+
+		for range crawler.Crawl() {
+		}
+
+		result, _ := crawler.Get()
+
+		return result
+	}
+
+	It("Adds the assets of the stylesheets and their imports", func() {
+		result := crawl()
+
+		Expect(result.Assets["styles"]).To(Equal([]*collect.Asset{
+			{URL: ts.URL + "/css/main.css", Type: collect.AssetInternal},
+			{URL: ts.URL + "/missing.css", Type: collect.AssetInternal},
+			{URL: ts.URL + "/private/hidden.css", Type: collect.AssetInternal},
+			{URL: other.URL + "/other.css", Type: collect.AssetExternal},
+			{URL: ts.URL + "/css/theme.css", Type: collect.AssetInternal},
+			{URL: ts.URL + "/css/main.css", Type: collect.AssetInternal},
+		}))
+		Expect(result.Assets["fonts"]).To(Equal([]*collect.Asset{
+			{URL: ts.URL + "/fonts/sans.woff2", Type: collect.AssetInternal},
+		}))
+		Expect(result.Assets["images"]).To(Equal([]*collect.Asset{
+			{URL: ts.URL + "/bg.png", Type: collect.AssetInternal},
+			{URL: ts.URL + "/css/logo.svg", Type: collect.AssetInternal},
+		}))
+	})
+
+	It("Requests every stylesheet once", func() {
+		result := crawl()
+
+		Expect(result.Children[0].Assets["fonts"]).To(HaveLen(1))
+
+		Expect(requests["/css/main.css"]).To(Equal(1))
+		Expect(requests["/css/theme.css"]).To(Equal(1))
+		Expect(requests["/missing.css"]).To(Equal(1))
+	})
+
+	It("Does not request the stylesheets of the other domains", func() {
+		crawl()
+
+		Expect(requests).ToNot(HaveKey("other/other.css"))
+	})
+
+	It("Does not request the stylesheets disallowed by robots.txt", func() {
+		crawl()
+
+		Expect(requests).ToNot(HaveKey("/private/hidden.css"))
+	})
+
+	It("Requests the stylesheets of the allowed domains", func() {
+		parsed, _ := url.Parse(other.URL)
+		result := crawl(WithDomains(parsed.Host))
+
+		Expect(requests["other/other.css"]).To(Equal(1))
+		Expect(result.Assets["fonts"]).To(ContainElement(&collect.Asset{
+			URL:  other.URL + "/other.woff",
+			Type: collect.AssetExternal,
+		}))
+	})
+
+	It("Does not request the stylesheets if it's disabled", func() {
+		result := crawl(WithStylesheets(false))
+
+		Expect(result.Assets["fonts"]).To(BeNil())
+		Expect(requests).ToNot(HaveKey("/css/main.css"))
+	})
+})